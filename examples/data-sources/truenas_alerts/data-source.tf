@@ -0,0 +1,10 @@
+# Fail the plan if there are any undismissed critical alerts
+data "truenas_alerts" "health" {
+  fail_on_level = "CRITICAL"
+}
+
+# List pool capacity warnings
+data "truenas_alerts" "capacity" {
+  classes = ["ZpoolCapacityWarning", "ZpoolCapacityCritical"]
+  include_dismissed = false
+}
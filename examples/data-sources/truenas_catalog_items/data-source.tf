@@ -0,0 +1,15 @@
+data "truenas_catalog_items" "official" {
+  catalog = "OFFICIAL"
+  trains  = ["charts"]
+}
+
+locals {
+  plex = one([for item in data.truenas_catalog_items.official.items : item if item.name == "plex"])
+}
+
+# Pin the app to the latest version known at plan time
+resource "truenas_app" "plex" {
+  release_name = "plex"
+  item         = "plex"
+  version      = local.plex.latest_version
+}
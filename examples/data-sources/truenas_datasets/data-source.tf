@@ -0,0 +1,16 @@
+# Share every dataset under Tank/shares that is tagged for SMB
+data "truenas_datasets" "shares" {
+  root      = "Tank/shares"
+  recursive = false
+  type      = "FILESYSTEM"
+  user_properties = {
+    "com.example:smb" = "*"
+  }
+}
+
+resource "truenas_share_smb" "share" {
+  for_each = { for ds in data.truenas_datasets.shares.datasets : ds.name => ds }
+
+  path = each.value.mount_point
+  name = each.value.name
+}
\ No newline at end of file
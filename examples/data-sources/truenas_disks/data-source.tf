@@ -0,0 +1,6 @@
+# Find spinning disks that are not part of any pool yet
+data "truenas_disks" "spare" {
+  type     = "HDD"
+  unused   = true
+  min_size = 4000000000000
+}
@@ -0,0 +1,14 @@
+resource "truenas_kerberos_realm" "ad" {
+  realm = "AD.EXAMPLE.COM"
+}
+
+resource "truenas_activedirectory" "default" {
+  domainname = "ad.example.com"
+  bindname = "Administrator"
+  bindpw = "<password>"
+  site = "Office"
+  kerberos_realm = truenas_kerberos_realm.ad.realm_id
+  netbiosname = "NAS"
+  createcomputer = "Computers/Servers"
+  idmap_backend = "RID"
+}
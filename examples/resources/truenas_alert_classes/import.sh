@@ -0,0 +1,4 @@
+terraform import truenas_alert_classes.default {{id}}
+
+# Example:
+terraform import truenas_alert_classes.default "1"
\ No newline at end of file
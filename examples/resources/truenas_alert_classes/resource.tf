@@ -0,0 +1,17 @@
+resource "truenas_alert_classes" "default" {
+  class {
+    name = "ZpoolCapacityWarning"
+    level = "CRITICAL"
+    policy = "IMMEDIATELY"
+  }
+
+  class {
+    name = "ScrubStarted"
+    policy = "NEVER"
+  }
+
+  class {
+    name = "SMART"
+    policy = "DAILY"
+  }
+}
@@ -0,0 +1,4 @@
+terraform import truenas_alert_service.default {{alert_service_id}}
+
+# Example:
+terraform import truenas_alert_service.default "1"
\ No newline at end of file
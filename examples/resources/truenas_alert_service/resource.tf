@@ -0,0 +1,34 @@
+resource "truenas_alert_service" "slack" {
+  name = "Slack #nas-alerts"
+  type = "Slack"
+  level = "WARNING"
+  test_on_apply = true
+
+  secret_attributes = {
+    url = "https://hooks.slack.com/services/T000/B000/XXXX"
+  }
+}
+
+resource "truenas_alert_service" "mail" {
+  name = "Ops mailbox"
+  type = "Mail"
+  level = "ERROR"
+
+  attributes = {
+    email = "ops@example.com"
+  }
+}
+
+resource "truenas_alert_service" "opsgenie" {
+  name = "OpsGenie"
+  type = "OpsGenie"
+  level = "CRITICAL"
+
+  attributes = {
+    api_url = "https://api.eu.opsgenie.com"
+  }
+
+  secret_attributes = {
+    api_key = "<opsgenie api key>"
+  }
+}
@@ -0,0 +1,4 @@
+terraform import truenas_app.default {{release_name}}
+
+# Example:
+terraform import truenas_app.default "plex"
\ No newline at end of file
@@ -0,0 +1,14 @@
+resource "truenas_app" "plex" {
+  release_name = "plex"
+  catalog      = "OFFICIAL"
+  train        = "charts"
+  item         = "plex"
+  version      = "1.7.25"
+
+  values = yamlencode({
+    timezone = "Europe/Vilnius"
+    plexConfig = {
+      claimToken = var.plex_claim_token
+    }
+  })
+}
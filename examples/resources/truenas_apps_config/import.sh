@@ -0,0 +1,4 @@
+terraform import truenas_apps_config.default {{id}}
+
+# Example:
+terraform import truenas_apps_config.default "1"
\ No newline at end of file
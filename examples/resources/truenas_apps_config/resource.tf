@@ -0,0 +1,5 @@
+resource "truenas_apps_config" "default" {
+  pool         = "tank"
+  cluster_cidr = "172.16.0.0/16"
+  node_ip      = "192.168.1.10"
+}
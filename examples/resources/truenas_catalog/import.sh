@@ -0,0 +1,4 @@
+terraform import truenas_catalog.default {{label}}
+
+# Example:
+terraform import truenas_catalog.default "TRUECHARTS"
\ No newline at end of file
@@ -0,0 +1,6 @@
+resource "truenas_catalog" "truecharts" {
+  label            = "TRUECHARTS"
+  repository       = "https://github.com/truecharts/catalog"
+  branch           = "main"
+  preferred_trains = ["stable"]
+}
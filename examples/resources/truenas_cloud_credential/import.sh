@@ -0,0 +1,4 @@
+terraform import truenas_cloud_credential.default {{credential_id}}
+
+# Example:
+terraform import truenas_cloud_credential.default "1"
\ No newline at end of file
@@ -0,0 +1,14 @@
+resource "truenas_cloud_credential" "s3" {
+  name = "Backups S3"
+  provider_type = "S3"
+
+  attributes = {
+    access_key_id = "<access key id>"
+    endpoint = "https://s3.example.com"
+    region = "us-east-1"
+  }
+
+  secret_attributes = {
+    secret_access_key = "<secret access key>"
+  }
+}
@@ -0,0 +1,4 @@
+terraform import truenas_cloudsync_task.default {{cloudsync_id}}
+
+# Example:
+terraform import truenas_cloudsync_task.default "1"
\ No newline at end of file
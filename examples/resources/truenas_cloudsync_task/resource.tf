@@ -0,0 +1,36 @@
+resource "truenas_cloudsync_task" "backup" {
+  description = "Nightly backup to S3"
+  path = "/mnt/Tank/backup"
+  credentials = truenas_cloud_credential.s3.credential_id
+  bucket = "nas-backups"
+  folder = "/tank"
+  direction = "PUSH"
+  transfer_mode = "SYNC"
+
+  encryption = true
+  filename_encryption = true
+  encryption_password = "<encryption password>"
+  encryption_salt = "<encryption salt>"
+
+  exclude = [
+    "*.tmp",
+    ".cache/**",
+  ]
+
+  bwlimit {
+    time = "08:00"
+    bandwidth = 1048576 // 1 MiB/s during work hours
+  }
+
+  bwlimit {
+    time = "18:00"
+  }
+
+  schedule {
+    minute = "0"
+    hour = "2"
+  }
+
+  pre_script = "echo starting backup"
+  enabled = true
+}
@@ -19,6 +19,18 @@ resource "truenas_dataset" "test" {
   readonly = "off"
   record_size = "256K"
   case_sensitivity = "mixed"
+  xattr = "sa"
+  dnode_size = "auto"
+  special_small_block_size = "32K"
+  primary_cache = "all"
+  log_bias = "latency"
+
+  deletion_protection = true
+
+  user_properties = {
+    "com.example:owner"       = "platform"
+    "com.example:backup-tier" = "gold"
+  }
 
   inherit_encryption = false
   encrypted = true
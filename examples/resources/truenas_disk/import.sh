@@ -0,0 +1,4 @@
+terraform import truenas_disk.default {{identifier}}
+
+# Example:
+terraform import truenas_disk.default "{serial}WD-WCC4N0123456"
\ No newline at end of file
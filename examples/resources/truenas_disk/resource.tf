@@ -0,0 +1,14 @@
+data "truenas_disks" "hdd" {
+  type = "HDD"
+}
+
+# Spin down all HDDs after 30 minutes of inactivity
+resource "truenas_disk" "hdd" {
+  for_each = { for disk in data.truenas_disks.hdd.disks : disk.name => disk }
+
+  identifier   = each.value.identifier
+  description  = "bay ${each.key}"
+  hddstandby   = "30"
+  advpowermgmt = "127"
+  critical     = 50
+}
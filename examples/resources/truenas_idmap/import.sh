@@ -0,0 +1,4 @@
+terraform import truenas_idmap.default {{idmap_id}}
+
+# Example:
+terraform import truenas_idmap.default "5"
\ No newline at end of file
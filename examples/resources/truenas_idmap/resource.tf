@@ -0,0 +1,25 @@
+resource "truenas_idmap" "branch" {
+  name = "BRANCH"
+  dns_domain_name = "branch.example.com"
+  idmap_backend = "RID"
+  range_low = 200000001
+  range_high = 300000000
+}
+
+resource "truenas_idmap" "legacy" {
+  name = "LEGACY"
+  idmap_backend = "LDAP"
+  range_low = 300000001
+  range_high = 400000000
+
+  options = {
+    ldap_url = "ldap://ldap.legacy.example.com"
+    ldap_base_dn = "dc=legacy,dc=example,dc=com"
+    ldap_user_dn = "cn=nas,dc=legacy,dc=example,dc=com"
+    readonly = "true"
+  }
+
+  secret_options = {
+    ldap_user_dn_password = "<password>"
+  }
+}
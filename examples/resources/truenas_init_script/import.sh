@@ -0,0 +1,4 @@
+terraform import truenas_init_script.default {{init_script_id}}
+
+# Example:
+terraform import truenas_init_script.default "1"
\ No newline at end of file
@@ -0,0 +1,13 @@
+resource "truenas_init_script" "offload" {
+  type = "COMMAND"
+  command = "ifconfig igb0 -tso -lro"
+  when = "POSTINIT"
+  comment = "Disable NIC offloading"
+}
+
+resource "truenas_init_script" "backup_config" {
+  type = "SCRIPT"
+  script = "/mnt/Tank/scripts/backup-config.sh"
+  when = "SHUTDOWN"
+  timeout = 60
+}
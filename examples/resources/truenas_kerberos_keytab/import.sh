@@ -0,0 +1,4 @@
+terraform import truenas_kerberos_keytab.default {{keytab_id}}
+
+# Example:
+terraform import truenas_kerberos_keytab.default "1"
\ No newline at end of file
@@ -0,0 +1,4 @@
+resource "truenas_kerberos_keytab" "nfs" {
+  name = "nfs"
+  file = filebase64("${path.module}/nas.keytab")
+}
@@ -0,0 +1,4 @@
+terraform import truenas_kerberos_realm.default {{realm_id}}
+
+# Example:
+terraform import truenas_kerberos_realm.default "1"
\ No newline at end of file
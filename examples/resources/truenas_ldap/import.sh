@@ -0,0 +1,4 @@
+terraform import truenas_ldap.default {{id}}
+
+# Example:
+terraform import truenas_ldap.default "1"
\ No newline at end of file
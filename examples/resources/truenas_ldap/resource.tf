@@ -0,0 +1,8 @@
+resource "truenas_ldap" "default" {
+  hostname = ["ldap1.example.com", "ldap2.example.com"]
+  basedn = "dc=example,dc=com"
+  binddn = "cn=nas,ou=services,dc=example,dc=com"
+  bindpw = "<password>"
+  ssl = "START_TLS"
+  schema = "RFC2307BIS"
+}
@@ -0,0 +1,12 @@
+resource "truenas_mail_config" "default" {
+  fromemail = "nas@example.com"
+  fromname = "TrueNAS"
+  outgoingserver = "smtp.example.com"
+  port = 587
+  security = "TLS"
+  smtp_auth = true
+  user = "nas@example.com"
+  pass = "<smtp password>"
+  root_email = "ops@example.com"
+  send_test_email = true
+}
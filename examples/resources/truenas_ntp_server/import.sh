@@ -0,0 +1,4 @@
+terraform import truenas_ntp_server.default {{ntp_server_id}}
+
+# Example:
+terraform import truenas_ntp_server.default "1"
\ No newline at end of file
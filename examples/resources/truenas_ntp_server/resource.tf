@@ -0,0 +1,5 @@
+resource "truenas_ntp_server" "internal" {
+  address = "ntp1.example.com"
+  iburst = true
+  prefer = true
+}
@@ -0,0 +1,4 @@
+terraform import truenas_rsync_module.default {{rsync_module_id}}
+
+# Example:
+terraform import truenas_rsync_module.default "1"
\ No newline at end of file
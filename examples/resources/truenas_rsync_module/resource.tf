@@ -0,0 +1,13 @@
+resource "truenas_rsync_module" "configs" {
+  name = "configs"
+  comment = "Configuration backups from legacy hosts"
+  path = "/mnt/Tank/configs"
+  mode = "RW"
+  maxconn = 4
+  user = "root"
+  group = "wheel"
+
+  hostsallow = [
+    "10.0.0.0/24",
+  ]
+}
@@ -0,0 +1,4 @@
+terraform import truenas_rsync_task.default {{rsync_task_id}}
+
+# Example:
+terraform import truenas_rsync_task.default "1"
\ No newline at end of file
@@ -0,0 +1,21 @@
+resource "truenas_rsync_task" "pull_configs" {
+  description = "Pull /etc from legacy server"
+  path = "/mnt/Tank/configs/legacy"
+  user = "root"
+  mode = "SSH"
+  remotehost = "root@legacy.example.com"
+  remoteport = 22
+  remotepath = "/etc"
+  direction = "PULL"
+  archive = true
+  delete = true
+
+  extra = [
+    "--exclude=*.tmp",
+  ]
+
+  schedule {
+    minute = "30"
+    hour = "2"
+  }
+}
@@ -0,0 +1,13 @@
+data "truenas_pool_ids" "all" {}
+
+resource "truenas_scrub_task" "tank" {
+  pool = tolist(data.truenas_pool_ids.all.ids)[0]
+  threshold = 35
+  description = "Weekly scrub"
+
+  schedule {
+    minute = "0"
+    hour = "0"
+    dow = "7"
+  }
+}
@@ -0,0 +1,7 @@
+resource "truenas_smart_config" "default" {
+  interval = 30
+  powermode = "STANDBY"
+  difference = 5
+  informational = 45
+  critical = 55
+}
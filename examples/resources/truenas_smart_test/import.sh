@@ -0,0 +1,4 @@
+terraform import truenas_smart_test.default {{smart_test_id}}
+
+# Example:
+terraform import truenas_smart_test.default "1"
\ No newline at end of file
@@ -0,0 +1,20 @@
+resource "truenas_smart_test" "short" {
+  all_disks = true
+  type = "SHORT"
+  description = "Daily short test"
+
+  schedule {
+    hour = "3"
+  }
+}
+
+resource "truenas_smart_test" "long" {
+  disks = ["{serial}WD-WCC4N0123456", "{serial}WD-WCC4N0654321"]
+  type = "LONG"
+  description = "Monthly long test"
+
+  schedule {
+    hour = "1"
+    dom = "1"
+  }
+}
@@ -0,0 +1,8 @@
+resource "truenas_ssh_config" "default" {
+  tcpport = 2222
+  bindiface = ["igb0"]
+  passwordauth = false
+  kerberosauth = true
+  sftp_log_level = "INFO"
+  sftp_log_facility = "AUTH"
+}
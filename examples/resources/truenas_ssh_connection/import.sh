@@ -0,0 +1,4 @@
+terraform import truenas_ssh_connection.default {{connection_id}}
+
+# Example:
+terraform import truenas_ssh_connection.default "2"
\ No newline at end of file
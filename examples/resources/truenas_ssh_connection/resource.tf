@@ -0,0 +1,21 @@
+resource "truenas_ssh_keypair" "replication" {
+  name = "replication"
+}
+
+# Manual setup, remote host key is scanned if not set
+resource "truenas_ssh_connection" "backup" {
+  name = "backup"
+  host = "backup.example.com"
+  port = 22
+  username = "replication"
+  private_key = truenas_ssh_keypair.replication.keypair_id
+}
+
+# Semi-automatic setup against a remote TrueNAS
+resource "truenas_ssh_connection" "nas2" {
+  name = "nas2"
+  setup_type = "SEMI-AUTOMATIC"
+  url = "https://nas2.example.com"
+  token = "<auth token>"
+  private_key = truenas_ssh_keypair.replication.keypair_id
+}
@@ -0,0 +1,4 @@
+terraform import truenas_ssh_keypair.default {{keypair_id}}
+
+# Example:
+terraform import truenas_ssh_keypair.default "1"
\ No newline at end of file
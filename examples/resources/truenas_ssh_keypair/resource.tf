@@ -0,0 +1,10 @@
+# Generated by TrueNAS
+resource "truenas_ssh_keypair" "replication" {
+  name = "replication"
+}
+
+# Imported
+resource "truenas_ssh_keypair" "backup" {
+  name = "backup"
+  private_key = file("${path.module}/id_ed25519")
+}
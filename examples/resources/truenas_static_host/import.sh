@@ -0,0 +1,4 @@
+terraform import truenas_static_host.default {{hostname}}
+
+# Example:
+terraform import truenas_static_host.default "backup.example.com"
\ No newline at end of file
@@ -0,0 +1,4 @@
+resource "truenas_static_host" "backup" {
+  hostname = "backup.example.com"
+  ip = "10.20.0.15"
+}
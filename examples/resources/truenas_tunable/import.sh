@@ -0,0 +1,4 @@
+terraform import truenas_tunable.default {{tunable_id}}
+
+# Example:
+terraform import truenas_tunable.default "1"
\ No newline at end of file
@@ -0,0 +1,15 @@
+# Applied immediately
+resource "truenas_tunable" "somaxconn" {
+  type = "SYSCTL"
+  var = "kern.ipc.somaxconn"
+  value = "2048"
+  comment = "Larger listen queue"
+}
+
+# Applied after reboot, a warning is shown when it changes
+resource "truenas_tunable" "arc_max" {
+  type = "LOADER"
+  var = "vfs.zfs.arc_max"
+  value = "68719476736"
+  comment = "Limit ARC to 64 GiB"
+}
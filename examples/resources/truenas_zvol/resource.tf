@@ -4,4 +4,8 @@ resource "truenas_zvol" "zv" {
   volsize = 1024 * 1024 * 1024 // 1GiB
   comments = "Test comment"
   compression = "lz4"
+
+  user_properties = {
+    "com.example:owner" = "platform"
+  }
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/dariusbakunas/terraform-provider-truenas/truenas"
+	"io"
+	"os"
+	"strings"
+)
+
+// runGenerate implements the generate command, which writes configuration and import blocks
+// for existing TrueNAS objects:
+//
+//	terraform-provider-truenas generate -root Tank/shares -out truenas.tf
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+
+	baseURL := fs.String("base-url", os.Getenv("TRUENAS_BASE_URL"), "TrueNAS API base URL, eg. https://your.nas/api/v2.0 (TRUENAS_BASE_URL)")
+	apiKey := fs.String("api-key", os.Getenv("TRUENAS_API_KEY"), "TrueNAS API key (TRUENAS_API_KEY)")
+	root := fs.String("root", "", "only adopt datasets and zvols under this dataset, and shares and VMs using only paths under it, eg. Tank/shares (cronjobs are always adopted)")
+	types := fs.String("types", "", fmt.Sprintf("comma separated resource types to generate, default: %s", strings.Join(truenas.GenerateTypes, ",")))
+	out := fs.String("out", "", "write configuration to this file instead of stdout")
+	debug := fs.Bool("debug", false, "dump all API requests/responses")
+
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if *baseURL == "" || *apiKey == "" {
+		return fmt.Errorf("base-url and api-key are required")
+	}
+
+	opts := truenas.GenerateOptions{Root: *root}
+
+	if *types != "" {
+		opts.Types = strings.Split(*types, ",")
+	}
+
+	var w io.Writer = os.Stdout
+
+	if *out != "" {
+		f, err := os.Create(*out)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		w = f
+	}
+
+	ctx := context.Background()
+
+	return truenas.Generate(ctx, truenas.NewAPIClient(ctx, *baseURL, *apiKey, *debug), opts, w)
+}
@@ -0,0 +1,245 @@
+package truenas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiError is returned by callAPI for unsuccessful responses, TrueNAS
+// puts validation errors into response body, so it is included in the message
+type apiError struct {
+	StatusCode int
+	status     string
+	body       []byte
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s\n%s", e.status, e.body)
+}
+
+func (e *apiError) Body() []byte {
+	return e.body
+}
+
+// callAPI sends a request to TrueNAS REST API endpoint that is not covered by truenas-go-sdk,
+// it reuses SDK client configuration (base URL, authentication and debug flag).
+// If body is not nil it is sent JSON encoded, if out is not nil response is decoded into it.
+func callAPI(ctx context.Context, c *api.APIClient, method string, path string, body interface{}, out interface{}) (*http.Response, error) {
+	cfg := c.GetConfig()
+
+	baseURL, err := cfg.ServerURL(0, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var reqBody *bytes.Buffer
+
+	if body != nil {
+		b, err := json.Marshal(body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		reqBody = bytes.NewBuffer(b)
+	} else {
+		reqBody = &bytes.Buffer{}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(baseURL, "/")+path, reqBody)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	for k, v := range cfg.DefaultHeader {
+		req.Header.Set(k, v)
+	}
+
+	if cfg.Debug {
+		dump, err := httputil.DumpRequestOut(req, true)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("\n%s\n", string(dump))
+	}
+
+	client := cfg.HTTPClient
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		return resp, err
+	}
+
+	if cfg.Debug {
+		dump, err := httputil.DumpResponse(resp, true)
+		if err != nil {
+			return resp, err
+		}
+		log.Printf("\n%s\n", string(dump))
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode >= 300 {
+		return resp, &apiError{
+			StatusCode: resp.StatusCode,
+			status:     resp.Status,
+			body:       respBody,
+		}
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp, fmt.Errorf("error decoding %s response: %s", path, err)
+		}
+	}
+
+	return resp, nil
+}
+
+// job is a subset of core.get_jobs entry fields
+type job struct {
+	ID        int             `json:"id"`
+	Method    string          `json:"method"`
+	State     string          `json:"state"`
+	Error     *string         `json:"error"`
+	Exception *string         `json:"exception"`
+	Result    json.RawMessage `json:"result"`
+}
+
+// waitForJob polls TrueNAS job until it succeeds, fails or timeout is reached,
+// raw job result is returned on success
+func waitForJob(ctx context.Context, c *api.APIClient, id int, timeout time.Duration) (json.RawMessage, error) {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"WAITING", "RUNNING"},
+		Target:  []string{"SUCCESS"},
+		Refresh: func() (interface{}, string, error) {
+			var jobs []job
+
+			_, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/core/get_jobs?id=%d", id), nil, &jobs)
+
+			if err != nil {
+				return nil, "", err
+			}
+
+			if len(jobs) == 0 {
+				return nil, "", fmt.Errorf("job %d not found", id)
+			}
+
+			j := jobs[0]
+
+			if j.State == "FAILED" || j.State == "ABORTED" {
+				msg := j.State
+
+				if j.Error != nil {
+					msg = *j.Error
+				}
+
+				return nil, j.State, fmt.Errorf("job %d (%s) failed: %s", id, j.Method, msg)
+			}
+
+			return j, j.State, nil
+		},
+		Timeout:    timeout,
+		Delay:      1 * time.Second,
+		MinTimeout: 2 * time.Second,
+	}
+
+	res, err := stateConf.WaitForStateContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(job).Result, nil
+}
+
+// callJobAPI calls an endpoint that starts a TrueNAS job and waits for it,
+// raw job result is returned on success
+func callJobAPI(ctx context.Context, c *api.APIClient, method string, path string, body interface{}, timeout time.Duration) (json.RawMessage, error) {
+	var jobID int
+
+	_, err := callAPI(ctx, c, method, path, body, &jobID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] Waiting for job %d (%s %s)", jobID, method, path)
+
+	return waitForJob(ctx, c, jobID, timeout)
+}
+
+// methodAttributes caches attributes accepted by middleware methods, keyed by client and method name
+var methodAttributes sync.Map
+
+type methodSchema struct {
+	Accepts []struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	} `json:"accepts"`
+}
+
+// getMethodAttributes returns names of object attributes accepted by a middleware method (eg. pool.dataset.create),
+// so that options missing in older TrueNAS versions can be detected. Result is cached for the lifetime of the client.
+func getMethodAttributes(ctx context.Context, c *api.APIClient, service string, method string) (map[string]bool, error) {
+	key := fmt.Sprintf("%p/%s.%s", c, service, method)
+
+	if attrs, ok := methodAttributes.Load(key); ok {
+		return attrs.(map[string]bool), nil
+	}
+
+	var methods map[string]methodSchema
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/core/get_methods", map[string]interface{}{"service": service}, &methods)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := methods[fmt.Sprintf("%s.%s", service, method)]
+
+	if !ok {
+		return nil, fmt.Errorf("method %s.%s not found", service, method)
+	}
+
+	attrs := map[string]bool{}
+
+	for _, arg := range m.Accepts {
+		for name := range arg.Properties {
+			attrs[name] = true
+		}
+	}
+
+	methodAttributes.Store(key, attrs)
+
+	return attrs, nil
+}
@@ -0,0 +1,123 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestAPIClient(url string) *api.APIClient {
+	config := api.NewConfiguration()
+	config.Servers = api.ServerConfigurations{
+		{
+			URL: url,
+		},
+	}
+	return api.NewAPIClient(config)
+}
+
+func Test_callAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/cloudsync/credentials/id/1":
+			fmt.Fprint(w, `{"id": 1, "name": "test", "provider": "S3", "attributes": {"region": "us-east-1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL + "/api/v2.0/")
+
+	var cred cloudCredential
+
+	_, err := callAPI(context.Background(), c, http.MethodGet, "/cloudsync/credentials/id/1", nil, &cred)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cloudCredential{ID: 1, Name: "test", Provider: "S3", Attributes: map[string]interface{}{"region": "us-east-1"}}, cred)
+
+	resp, err := callAPI(context.Background(), c, http.MethodGet, "/cloudsync/credentials/id/2", nil, &cred)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, err.Error(), "not found")
+	}
+}
+
+func Test_waitForJob(t *testing.T) {
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("id") {
+		case "1":
+			calls++
+			if calls < 2 {
+				fmt.Fprint(w, `[{"id": 1, "method": "test.job", "state": "RUNNING", "result": null}]`)
+			} else {
+				fmt.Fprint(w, `[{"id": 1, "method": "test.job", "state": "SUCCESS", "result": {"done": true}}]`)
+			}
+		case "2":
+			fmt.Fprint(w, `[{"id": 2, "method": "test.job", "state": "FAILED", "error": "[EFAULT] boom"}]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL)
+
+	res, err := waitForJob(context.Background(), c, 1, 30*time.Second)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"done": true}`, string(res))
+
+	_, err = waitForJob(context.Background(), c, 2, 30*time.Second)
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "[EFAULT] boom")
+	}
+
+	_, err = waitForJob(context.Background(), c, 3, 30*time.Second)
+
+	assert.Error(t, err)
+}
+
+func Test_getMethodAttributes(t *testing.T) {
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{
+			"pool.dataset.create": {"accepts": [{"type": "object", "properties": {"name": {}, "xattr": {}}}]},
+			"pool.dataset.update": {"accepts": [{"type": "string"}, {"type": "object", "properties": {"checksum": {}}}]}
+		}`)
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL + "/api/v2.0/")
+
+	attrs, err := getMethodAttributes(context.Background(), c, "pool.dataset", "create")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"name": true, "xattr": true}, attrs)
+
+	attrs, err = getMethodAttributes(context.Background(), c, "pool.dataset", "update")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"checksum": true}, attrs)
+
+	_, err = getMethodAttributes(context.Background(), c, "pool.dataset", "create")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	_, err = getMethodAttributes(context.Background(), c, "pool.dataset", "delete")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type alertDate struct {
+	Date int64 `json:"$date"`
+}
+
+type alert struct {
+	UUID      string    `json:"uuid"`
+	Klass     string    `json:"klass"`
+	Level     string    `json:"level"`
+	Formatted *string   `json:"formatted"`
+	Dismissed bool      `json:"dismissed"`
+	Datetime  alertDate `json:"datetime"`
+}
+
+func dataSourceTrueNASAlerts() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get current TrueNAS alerts, optionally fail the plan if there are alerts at or above given level",
+		ReadContext: dataSourceTrueNASAlertsRead,
+		Schema: map[string]*schema.Schema{
+			"levels": &schema.Schema{
+				Description: "Only return alerts with these levels",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(alertLevels, false),
+				},
+			},
+			"classes": &schema.Schema{
+				Description: "Only return alerts of these classes, eg. `ZpoolCapacityWarning`",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"include_dismissed": &schema.Schema{
+				Description: "Include dismissed alerts",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"fail_on_level": &schema.Schema{
+				Description:  "Fail with an error if any alert that is not dismissed has this level or higher, regardless of other filters: " + strings.Join(alertLevels, ", "),
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(alertLevels, false),
+			},
+			"alerts": &schema.Schema{
+				Description: "Alerts",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": &schema.Schema{
+							Description: "Alert UUID",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"klass": &schema.Schema{
+							Description: "Alert class",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"level": &schema.Schema{
+							Description: "Alert level",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"formatted": &schema.Schema{
+							Description: "Alert text",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"dismissed": &schema.Schema{
+							Description: "`true` if alert was dismissed",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"datetime": &schema.Schema{
+							Description: "Time when alert was raised (RFC3339)",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTrueNASAlertsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var alerts []alert
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/alert/list", nil, &alerts)
+
+	if err != nil {
+		return diag.Errorf("error getting alerts: %s", err)
+	}
+
+	filtered := filterAlerts(
+		alerts,
+		expandStrings(d.Get("levels").(*schema.Set).List()),
+		expandStrings(d.Get("classes").(*schema.Set).List()),
+		d.Get("include_dismissed").(bool),
+	)
+
+	if err := d.Set("alerts", flattenAlerts(filtered)); err != nil {
+		return diag.Errorf("error setting alerts: %s", err)
+	}
+
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	// filters only limit returned alerts, any active alert at or above the level fails the read
+	if level, ok := d.GetOk("fail_on_level"); ok {
+		for _, a := range alertsAtOrAbove(alerts, level.(string)) {
+			text := a.Klass
+
+			if a.Formatted != nil {
+				text = *a.Formatted
+			}
+
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("TrueNAS %s alert: %s", a.Level, a.Klass),
+				Detail:   text,
+			})
+		}
+	}
+
+	return diags
+}
+
+func filterAlerts(alerts []alert, levels []string, classes []string, includeDismissed bool) []alert {
+	result := make([]alert, 0, len(alerts))
+
+	for _, a := range alerts {
+		if len(levels) > 0 && !containsString(levels, a.Level) {
+			continue
+		}
+
+		if len(classes) > 0 && !containsString(classes, a.Klass) {
+			continue
+		}
+
+		if a.Dismissed && !includeDismissed {
+			continue
+		}
+
+		result = append(result, a)
+	}
+
+	return result
+}
+
+// alertsAtOrAbove returns alerts that are not dismissed and have given level or higher
+func alertsAtOrAbove(alerts []alert, level string) []alert {
+	var result []alert
+
+	min := alertLevelIndex(level)
+
+	for _, a := range alerts {
+		if !a.Dismissed && alertLevelIndex(a.Level) >= min {
+			result = append(result, a)
+		}
+	}
+
+	return result
+}
+
+func alertLevelIndex(level string) int {
+	for i, l := range alertLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+func flattenAlerts(alerts []alert) []interface{} {
+	result := make([]interface{}, 0, len(alerts))
+
+	for _, a := range alerts {
+		formatted := ""
+
+		if a.Formatted != nil {
+			formatted = *a.Formatted
+		}
+
+		result = append(result, map[string]interface{}{
+			"uuid":      a.UUID,
+			"klass":     a.Klass,
+			"level":     a.Level,
+			"formatted": formatted,
+			"dismissed": a.Dismissed,
+			"datetime":  time.UnixMilli(a.Datetime.Date).UTC().Format(time.RFC3339),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,116 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccDataSourceTruenasAlerts_basic(t *testing.T) {
+	resourceName := "data.truenas_alerts.all"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "truenas_alerts" "all" {
+						levels = ["INFO", "NOTICE", "WARNING"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "alerts.#"),
+				),
+			},
+		},
+	})
+}
+
+func Test_filterAlerts(t *testing.T) {
+	alerts := []alert{
+		{UUID: "1", Klass: "ZpoolCapacityWarning", Level: "WARNING"},
+		{UUID: "2", Klass: "ZpoolCapacityCritical", Level: "CRITICAL", Dismissed: true},
+		{UUID: "3", Klass: "SMART", Level: "CRITICAL"},
+	}
+
+	assert.Len(t, filterAlerts(alerts, nil, nil, true), 3)
+	assert.Len(t, filterAlerts(alerts, nil, nil, false), 2)
+	assert.Equal(t, "3", filterAlerts(alerts, []string{"CRITICAL"}, nil, false)[0].UUID)
+	assert.Equal(t, "1", filterAlerts(alerts, nil, []string{"ZpoolCapacityWarning"}, true)[0].UUID)
+}
+
+func Test_alertsAtOrAbove(t *testing.T) {
+	alerts := []alert{
+		{UUID: "1", Klass: "ZpoolCapacityWarning", Level: "WARNING"},
+		{UUID: "2", Klass: "ZpoolCapacityCritical", Level: "CRITICAL", Dismissed: true},
+		{UUID: "3", Klass: "SMART", Level: "ERROR"},
+	}
+
+	assert.Len(t, alertsAtOrAbove(alerts, "INFO"), 2)
+	assert.Len(t, alertsAtOrAbove(alerts, "ERROR"), 1)
+	assert.Len(t, alertsAtOrAbove(alerts, "CRITICAL"), 0)
+}
+
+func Test_dataSourceTrueNASAlertsRead_failOnLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"uuid": "1", "klass": "Update", "level": "INFO", "dismissed": false, "datetime": {"$date": 1666000000000}},
+			{"uuid": "2", "klass": "SMART", "level": "CRITICAL", "dismissed": false, "datetime": {"$date": 1666000000000}},
+			{"uuid": "3", "klass": "ZpoolCapacityWarning", "level": "WARNING", "dismissed": true, "datetime": {"$date": 1666000000000}}
+		]`)
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL)
+	ds := dataSourceTrueNASAlerts()
+
+	// alert filtered out of the result still fails the read
+	d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"levels":        []interface{}{"INFO"},
+		"fail_on_level": "CRITICAL",
+	})
+
+	diags := ds.ReadContext(context.Background(), d, c)
+
+	assert.True(t, diags.HasError())
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "TrueNAS CRITICAL alert: SMART", diags[0].Summary)
+	assert.Equal(t, 1, d.Get("alerts.#"))
+
+	// dismissed alerts never fail the read
+	d = schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{
+		"include_dismissed": true,
+		"classes":           []interface{}{"ZpoolCapacityWarning"},
+		"fail_on_level":     "WARNING",
+	})
+
+	diags = ds.ReadContext(context.Background(), d, c)
+
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "TrueNAS CRITICAL alert: SMART", diags[0].Summary)
+}
+
+func Test_flattenAlerts(t *testing.T) {
+	text := "Space usage for pool \"Tank\" is 81%."
+
+	result := flattenAlerts([]alert{
+		{UUID: "1", Klass: "ZpoolCapacityWarning", Level: "WARNING", Formatted: &text, Datetime: alertDate{Date: 1666000000000}},
+	})
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"uuid":      "1",
+			"klass":     "ZpoolCapacityWarning",
+			"level":     "WARNING",
+			"formatted": text,
+			"dismissed": false,
+			"datetime":  "2022-10-17T09:46:40Z",
+		},
+	}, result)
+}
@@ -0,0 +1,232 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type catalogItem struct {
+	Name             string                     `json:"name"`
+	Description      string                     `json:"description"`
+	Healthy          bool                       `json:"healthy"`
+	Categories       []string                   `json:"categories"`
+	LatestVersion    string                     `json:"latest_version"`
+	LatestAppVersion string                     `json:"latest_app_version"`
+	Versions         map[string]json.RawMessage `json:"versions"`
+}
+
+func dataSourceTrueNASCatalogItems() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get items (apps) and their versions available in a catalog, can be used to pin `truenas_app` versions",
+		ReadContext: dataSourceTrueNASCatalogItemsRead,
+		Schema: map[string]*schema.Schema{
+			"catalog": &schema.Schema{
+				Description: "Catalog label",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"trains": &schema.Schema{
+				Description: "Only return items from these trains, all trains are returned if not set",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"items": &schema.Schema{
+				Description: "Catalog items",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"train": &schema.Schema{
+							Description: "Train name",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": &schema.Schema{
+							Description: "Item name",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"description": &schema.Schema{
+							Description: "Item description",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"healthy": &schema.Schema{
+							Description: "`true` if item passed catalog validation",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+						"categories": &schema.Schema{
+							Description: "Item categories",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"latest_version": &schema.Schema{
+							Description: "Latest item (chart) version",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"latest_app_version": &schema.Schema{
+							Description: "Application version of the latest item version",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"versions": &schema.Schema{
+							Description: "Available item versions, newest first",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTrueNASCatalogItemsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	label := d.Get("catalog").(string)
+
+	options := map[string]interface{}{
+		"cache":               true,
+		"retrieve_all_trains": true,
+	}
+
+	if v, ok := d.GetOk("trains"); ok {
+		options["retrieve_all_trains"] = false
+		options["trains"] = v
+	}
+
+	input := map[string]interface{}{
+		"label":   label,
+		"options": options,
+	}
+
+	var res json.RawMessage
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/catalog/items", input, &res)
+
+	if err != nil {
+		return diag.Errorf("error getting catalog items: %s", err)
+	}
+
+	// some releases list items in a job
+	var jobID int
+
+	if err := json.Unmarshal(res, &jobID); err == nil {
+		res, err = waitForJob(ctx, c, jobID, d.Timeout(schema.TimeoutRead))
+
+		if err != nil {
+			return diag.Errorf("error getting catalog items: %s", err)
+		}
+	}
+
+	var trains map[string]map[string]catalogItem
+
+	if err := json.Unmarshal(res, &trains); err != nil {
+		return diag.Errorf("error decoding catalog items: %s", err)
+	}
+
+	if err := d.Set("items", flattenCatalogItems(trains)); err != nil {
+		return diag.Errorf("error setting items: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s", label, strconv.FormatInt(time.Now().Unix(), 10)))
+
+	return diags
+}
+
+// flattenCatalogItems returns items sorted by train and name, versions newest first
+func flattenCatalogItems(trains map[string]map[string]catalogItem) []interface{} {
+	trainNames := make([]string, 0, len(trains))
+
+	for name := range trains {
+		trainNames = append(trainNames, name)
+	}
+
+	sort.Strings(trainNames)
+
+	result := make([]interface{}, 0)
+
+	for _, train := range trainNames {
+		names := make([]string, 0, len(trains[train]))
+
+		for name := range trains[train] {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			item := trains[train][name]
+
+			if item.Name == "" {
+				item.Name = name
+			}
+
+			result = append(result, map[string]interface{}{
+				"train":              train,
+				"name":               item.Name,
+				"description":        item.Description,
+				"healthy":            item.Healthy,
+				"categories":         item.Categories,
+				"latest_version":     item.LatestVersion,
+				"latest_app_version": item.LatestAppVersion,
+				"versions":           sortCatalogItemVersions(item.Versions),
+			})
+		}
+	}
+
+	return result
+}
+
+func sortCatalogItemVersions(versions map[string]json.RawMessage) []string {
+	result := make([]string, 0, len(versions))
+
+	for v := range versions {
+		result = append(result, v)
+	}
+
+	sort.Strings(result)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		vi, erri := version.NewVersion(result[i])
+		vj, errj := version.NewVersion(result[j])
+
+		// unparsable versions go last, in lexical order
+		if erri != nil || errj != nil {
+			if erri == nil {
+				return true
+			}
+			if errj == nil {
+				return false
+			}
+			return result[i] < result[j]
+		}
+
+		return vi.GreaterThan(vj)
+	})
+
+	return result
+}
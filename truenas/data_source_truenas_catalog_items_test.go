@@ -0,0 +1,55 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccDataSourceTruenasCatalogItems_basic(t *testing.T) {
+	resourceName := "data.truenas_catalog_items.official"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "truenas_catalog_items" "official" {
+						catalog = "OFFICIAL"
+						trains  = ["charts"]
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "items.#"),
+					resource.TestCheckResourceAttr(resourceName, "items.0.train", "charts"),
+					resource.TestCheckResourceAttrSet(resourceName, "items.0.latest_version"),
+				),
+			},
+		},
+	})
+}
+
+func Test_flattenCatalogItems(t *testing.T) {
+	var trains map[string]map[string]catalogItem
+
+	err := json.Unmarshal([]byte(`{
+		"test": {"minio": {"name": "minio", "latest_version": "1.0.0", "versions": {"1.0.0": {}}}},
+		"charts": {
+			"plex": {"name": "plex", "healthy": true, "latest_version": "1.7.25", "versions": {"1.7.9": {}, "1.7.25": {}, "1.6.0": {}}},
+			"minio": {"name": "minio", "latest_version": "1.6.58", "versions": {"1.6.58": {}}}
+		}
+	}`), &trains)
+
+	assert.NoError(t, err)
+
+	items := flattenCatalogItems(trains)
+
+	assert.Len(t, items, 3)
+	assert.Equal(t, "charts", items[0].(map[string]interface{})["train"])
+	assert.Equal(t, "minio", items[0].(map[string]interface{})["name"])
+	assert.Equal(t, "plex", items[1].(map[string]interface{})["name"])
+	assert.Equal(t, []string{"1.7.25", "1.7.9", "1.6.0"}, items[1].(map[string]interface{})["versions"])
+	assert.Equal(t, "test", items[2].(map[string]interface{})["train"])
+}
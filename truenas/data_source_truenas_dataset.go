@@ -12,7 +12,7 @@ import (
 func dataSourceTrueNASDataset() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceTrueNASDatasetRead,
-		Schema: map[string]*schema.Schema{
+		Schema: withDatasetSpaceUsage(map[string]*schema.Schema{
 			"dataset_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -29,6 +29,10 @@ func dataSourceTrueNASDataset() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"acl_inherit": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"acl_mode": &schema.Schema{
 				Type:        schema.TypeString,
 				Description: "Determine how chmod behaves when adjusting file ACLs. See the zfs(8) aclmode property.",
@@ -48,6 +52,10 @@ func dataSourceTrueNASDataset() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"checksum": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"comments": &schema.Schema{
 				Description: "Any notes about this dataset.",
 				Type:        schema.TypeString,
@@ -67,6 +75,10 @@ func dataSourceTrueNASDataset() *schema.Resource {
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"dnode_size": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"encrypted": &schema.Schema{
 				Type:     schema.TypeBool,
 				Computed: true,
@@ -117,6 +129,10 @@ func dataSourceTrueNASDataset() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"log_bias": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"managed_by": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -125,10 +141,18 @@ func dataSourceTrueNASDataset() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"normalization": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"origin": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"primary_cache": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"quota_bytes": &schema.Schema{
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -141,6 +165,10 @@ func dataSourceTrueNASDataset() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"redundant_metadata": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"ref_quota_bytes": &schema.Schema{
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -175,21 +203,38 @@ func dataSourceTrueNASDataset() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"secondary_cache": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"snap_dir": &schema.Schema{
 				Description: ".zfs snapshot directory visibility.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"special_small_block_size": &schema.Schema{
+				Description: "Blocks up to this size are stored on special allocation class vdevs",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
 			"sync": &schema.Schema{
 				Description: "`standard` uses the sync settings that have been requested by the client software, `always` waits for data writes to complete, and `disabled` never waits for writes to complete.",
 				Type:        schema.TypeString,
 				Computed:    true,
 			},
+			"user_properties": &schema.Schema{
+				Description: "Locally set ZFS user properties",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"xattr": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-		},
+		}),
 	}
 }
 
@@ -212,25 +257,25 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		d.Set("locked", *resp.Locked)
 	}
 
-	if resp.Aclmode != nil {
+	if resp.Aclmode != nil && resp.Aclmode.Value != nil {
 		if err := d.Set("acl_mode", strings.ToLower(*resp.Aclmode.Value)); err != nil {
 			return diag.Errorf("error setting acl_mode: %s", err)
 		}
 	}
 
-	if resp.Acltype != nil {
+	if resp.Acltype != nil && resp.Acltype.Value != nil {
 		if err := d.Set("acl_type", strings.ToLower(*resp.Acltype.Value)); err != nil {
 			return diag.Errorf("error setting acl_type: %s", err)
 		}
 	}
 
-	if resp.Atime != nil {
+	if resp.Atime != nil && resp.Atime.Value != nil {
 		if err := d.Set("atime", strings.ToLower(*resp.Atime.Value)); err != nil {
 			return diag.Errorf("error setting atime: %s", err)
 		}
 	}
 
-	if resp.Casesensitivity != nil {
+	if resp.Casesensitivity != nil && resp.Casesensitivity.Value != nil {
 		if err := d.Set("case_sensitivity", strings.ToLower(*resp.Casesensitivity.Value)); err != nil {
 			return diag.Errorf("error setting case_sensitivity: %s", err)
 		}
@@ -243,19 +288,19 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.Compression != nil {
+	if resp.Compression != nil && resp.Compression.Value != nil {
 		if err := d.Set("compression", strings.ToLower(*resp.Compression.Value)); err != nil {
 			return diag.Errorf("error setting compression: %s", err)
 		}
 	}
 
-	if resp.Deduplication != nil {
+	if resp.Deduplication != nil && resp.Deduplication.Value != nil {
 		if err := d.Set("deduplication", strings.ToLower(*resp.Deduplication.Value)); err != nil {
 			return diag.Errorf("error setting deduplication: %s", err)
 		}
 	}
 
-	if resp.Exec != nil {
+	if resp.Exec != nil && resp.Exec.Value != nil {
 		if err := d.Set("exec", strings.ToLower(*resp.Exec.Value)); err != nil {
 			return diag.Errorf("error setting exec: %s", err)
 		}
@@ -273,7 +318,7 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.Copies != nil {
+	if resp.Copies != nil && resp.Copies.Value != nil {
 		copies, err := strconv.Atoi(*resp.Copies.Value)
 
 		if err != nil {
@@ -297,7 +342,7 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.QuotaCritical != nil {
+	if resp.QuotaCritical != nil && resp.QuotaCritical.Value != nil {
 		quota, err := strconv.Atoi(*resp.QuotaCritical.Value)
 
 		if err != nil {
@@ -309,7 +354,7 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.QuotaWarning != nil {
+	if resp.QuotaWarning != nil && resp.QuotaWarning.Value != nil {
 		quota, err := strconv.Atoi(*resp.QuotaWarning.Value)
 
 		if err != nil {
@@ -359,7 +404,7 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.RefquotaCritical != nil {
+	if resp.RefquotaCritical != nil && resp.RefquotaCritical.Value != nil {
 		quota, err := strconv.Atoi(*resp.RefquotaCritical.Value)
 
 		if err != nil {
@@ -371,7 +416,7 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.RefquotaWarning != nil {
+	if resp.RefquotaWarning != nil && resp.RefquotaWarning.Value != nil {
 		quota, err := strconv.Atoi(*resp.RefquotaWarning.Value)
 
 		if err != nil {
@@ -383,13 +428,13 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.Readonly != nil {
+	if resp.Readonly != nil && resp.Readonly.Value != nil {
 		if err := d.Set("readonly", strings.ToLower(*resp.Readonly.Value)); err != nil {
 			return diag.Errorf("error setting readonly: %s", err)
 		}
 	}
 
-	if resp.Recordsize != nil {
+	if resp.Recordsize != nil && resp.Recordsize.Value != nil {
 		if err := d.Set("record_size", *resp.Recordsize.Value); err != nil {
 			return diag.Errorf("error setting record_size: %s", err)
 		}
@@ -405,13 +450,13 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.Sync != nil {
+	if resp.Sync != nil && resp.Sync.Value != nil {
 		if err := d.Set("sync", strings.ToLower(*resp.Sync.Value)); err != nil {
 			return diag.Errorf("error setting sync: %s", err)
 		}
 	}
 
-	if resp.Snapdir != nil {
+	if resp.Snapdir != nil && resp.Snapdir.Value != nil {
 		if err := d.Set("snap_dir", strings.ToLower(*resp.Snapdir.Value)); err != nil {
 			return diag.Errorf("error setting snap_dir: %s", err)
 		}
@@ -423,7 +468,7 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.Pbkdf2iters != nil {
+	if resp.Pbkdf2iters != nil && resp.Pbkdf2iters.Value != nil {
 		iters, err := strconv.Atoi(*resp.Pbkdf2iters.Value)
 
 		if err != nil {
@@ -437,22 +482,38 @@ func updateDatasetResourceFromResponse(resp *api.Dataset, d *schema.ResourceData
 		}
 	}
 
-	if resp.Origin != nil {
+	if resp.Origin != nil && resp.Origin.Value != nil {
 		if err := d.Set("origin", strings.ToLower(*resp.Origin.Value)); err != nil {
 			return diag.Errorf("error setting origin: %s", err)
 		}
 	}
 
-	if resp.Xattr != nil {
+	if resp.Xattr != nil && resp.Xattr.Value != nil {
 		if err := d.Set("xattr", strings.ToLower(*resp.Xattr.Value)); err != nil {
 			return diag.Errorf("error setting xattr: %s", err)
 		}
 	}
 
+	for k, attr := range zfsDatasetProperties {
+		if v := getZFSDatasetProperty(resp, attr); v != nil && k != "xattr" {
+			if err := d.Set(k, flattenZFSDatasetProperty(d, k, v)); err != nil {
+				return diag.Errorf("error setting %s: %s", k, err)
+			}
+		}
+	}
+
 	if resp.Encrypted != nil {
 		d.Set("encrypted", *resp.Encrypted)
 	}
 
+	if err := d.Set("user_properties", flattenUserProperties(resp)); err != nil {
+		return diag.Errorf("error setting user_properties: %s", err)
+	}
+
+	if err := setDatasetSpaceUsage(resp, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return diags
 }
 
@@ -0,0 +1,300 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func dataSourceTrueNASDatasets() *schema.Resource {
+	return &schema.Resource{
+		Description: "List datasets and zvols under a root dataset or pool",
+		ReadContext: dataSourceTrueNASDatasetsRead,
+		Schema: map[string]*schema.Schema{
+			"root": &schema.Schema{
+				Description: "Pool name or dataset ID to list children of, eg. `Tank` or `Tank/apps`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"include_root": &schema.Schema{
+				Description: "Include root dataset itself in the results",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"recursive": &schema.Schema{
+				Description: "List all descendants, not only direct children",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"type": &schema.Schema{
+				Description:  "Only return datasets of this type: `FILESYSTEM` or `VOLUME`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"FILESYSTEM", "VOLUME"}, false),
+			},
+			"name_regex": &schema.Schema{
+				Description:  "Only return datasets with IDs matching this regular expression",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"user_properties": &schema.Schema{
+				Description: "Only return datasets with all of these user properties set to given values, `*` matches any value",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"encryption": &schema.Schema{
+				Description:  "Only return datasets in this encryption state: `encrypted`, `unencrypted`, `locked` or `unlocked` (encrypted with key loaded)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"encrypted", "unencrypted", "locked", "unlocked"}, false),
+			},
+			"datasets": &schema.Schema{
+				Description: "Matching datasets, sorted by ID",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: datasetsElemSchema(),
+				},
+			},
+		},
+	}
+}
+
+// datasetsElemSchema returns truenas_dataset data source schema with all attributes computed,
+// extended with dataset type and space usage
+func datasetsElemSchema() map[string]*schema.Schema {
+	result := map[string]*schema.Schema{}
+
+	for k, v := range dataSourceTrueNASDataset().Schema {
+		result[k] = &schema.Schema{
+			Description: v.Description,
+			Type:        v.Type,
+			Elem:        v.Elem,
+			Computed:    true,
+		}
+	}
+
+	result["type"] = &schema.Schema{
+		Description: "Dataset type: `FILESYSTEM` or `VOLUME`",
+		Type:        schema.TypeString,
+		Computed:    true,
+	}
+
+	return result
+}
+
+type datasetFilter struct {
+	root           string
+	includeRoot    bool
+	recursive      bool
+	datasetType    string
+	nameRegex      *regexp.Regexp
+	userProperties map[string]string
+	encryption     string
+}
+
+func dataSourceTrueNASDatasetsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	resp, _, err := c.DatasetApi.ListDatasets(ctx).Execute()
+
+	if err != nil {
+		var body []byte
+		if apiErr, ok := err.(*api.GenericOpenAPIError); ok {
+			body = apiErr.Body()
+		}
+		return diag.Errorf("error getting datasets: %s\n%s", err, body)
+	}
+
+	filter := datasetFilter{
+		root:           strings.Trim(d.Get("root").(string), "/"),
+		includeRoot:    d.Get("include_root").(bool),
+		recursive:      d.Get("recursive").(bool),
+		datasetType:    d.Get("type").(string),
+		userProperties: convertStringMap(d.Get("user_properties").(map[string]interface{})),
+		encryption:     d.Get("encryption").(string),
+	}
+
+	if re, ok := d.GetOk("name_regex"); ok {
+		filter.nameRegex = regexp.MustCompile(re.(string))
+	}
+
+	datasets := filterDatasets(flattenDatasetTree(resp), filter)
+
+	elem := &schema.Resource{Schema: datasetsElemSchema()}
+	result := make([]interface{}, 0, len(datasets))
+
+	for i := range datasets {
+		item, err := flattenDatasetsItem(elem, &datasets[i])
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		result = append(result, item)
+	}
+
+	if err := d.Set("datasets", result); err != nil {
+		return diag.Errorf("error setting datasets: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s", filter.root, strconv.FormatInt(time.Now().Unix(), 10)))
+
+	return diags
+}
+
+// flattenDatasetTree appends nested children to the list, depending on query options
+// TrueNAS returns them nested, flattened or both
+func flattenDatasetTree(datasets []api.Dataset) []api.Dataset {
+	result := make([]api.Dataset, 0, len(datasets))
+
+	for _, ds := range datasets {
+		result = append(result, ds)
+
+		children, ok := ds.AdditionalProperties["children"].([]interface{})
+
+		if !ok || len(children) == 0 {
+			continue
+		}
+
+		b, err := json.Marshal(children)
+
+		if err != nil {
+			continue
+		}
+
+		var nested []api.Dataset
+
+		if err := json.Unmarshal(b, &nested); err == nil {
+			result = append(result, flattenDatasetTree(nested)...)
+		}
+	}
+
+	return result
+}
+
+// filterDatasets returns unique datasets matching filter sorted by ID
+func filterDatasets(datasets []api.Dataset, f datasetFilter) []api.Dataset {
+	seen := map[string]bool{}
+	result := make([]api.Dataset, 0)
+
+	for _, ds := range datasets {
+		if seen[ds.Id] {
+			continue
+		}
+
+		seen[ds.Id] = true
+
+		if !isDatasetUnderRoot(ds.Id, f.root, f.includeRoot, f.recursive) {
+			continue
+		}
+
+		if f.datasetType != "" && ds.Type != f.datasetType {
+			continue
+		}
+
+		if f.nameRegex != nil && !f.nameRegex.MatchString(ds.Id) {
+			continue
+		}
+
+		if !matchDatasetEncryption(ds, f.encryption) {
+			continue
+		}
+
+		if !matchUserProperties(flattenUserProperties(&ds), f.userProperties) {
+			continue
+		}
+
+		result = append(result, ds)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+
+	return result
+}
+
+func isDatasetUnderRoot(id string, root string, includeRoot bool, recursive bool) bool {
+	if id == root {
+		return includeRoot
+	}
+
+	if !strings.HasPrefix(id, root+"/") {
+		return false
+	}
+
+	return recursive || !strings.Contains(strings.TrimPrefix(id, root+"/"), "/")
+}
+
+func matchDatasetEncryption(ds api.Dataset, state string) bool {
+	encrypted := ds.Encrypted != nil && *ds.Encrypted
+	locked := ds.Locked != nil && *ds.Locked
+
+	switch state {
+	case "encrypted":
+		return encrypted
+	case "unencrypted":
+		return !encrypted
+	case "locked":
+		return encrypted && locked
+	case "unlocked":
+		return encrypted && !locked
+	}
+
+	return true
+}
+
+func matchUserProperties(props map[string]string, filter map[string]string) bool {
+	for k, v := range filter {
+		value, ok := props[k]
+
+		if !ok || (v != "*" && v != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// flattenDatasetsItem reuses updateDatasetResourceFromResponse to build a single list item
+func flattenDatasetsItem(elem *schema.Resource, ds *api.Dataset) (map[string]interface{}, error) {
+	rd := elem.Data(nil)
+
+	if diags := updateDatasetResourceFromResponse(ds, rd); diags.HasError() {
+		return nil, fmt.Errorf("error reading dataset %s: %s", ds.Id, diags[0].Summary)
+	}
+
+	dpath := newDatasetPath(ds.Id)
+
+	rd.Set("dataset_id", ds.Id)
+	rd.Set("pool", dpath.Pool)
+	rd.Set("parent", dpath.Parent)
+	rd.Set("name", dpath.Name)
+	rd.Set("type", ds.Type)
+
+	result := map[string]interface{}{}
+
+	for k := range elem.Schema {
+		result[k] = rd.Get(k)
+	}
+
+	return result, nil
+}
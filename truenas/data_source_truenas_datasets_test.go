@@ -0,0 +1,125 @@
+package truenas
+
+import (
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestAccDataSourceTruenasDatasets_basic(t *testing.T) {
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	name := fmt.Sprintf("%s-%s", testResourcePrefix, suffix)
+	resourceName := "data.truenas_datasets.children"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+					resource "truenas_dataset" "root" {
+						pool = "%s"
+						name = "%s"
+					}
+
+					resource "truenas_dataset" "child" {
+						pool   = "%s"
+						parent = truenas_dataset.root.name
+						name   = "child"
+						user_properties = {
+							"com.example:share" = "yes"
+						}
+					}
+
+					data "truenas_datasets" "children" {
+						root = truenas_dataset.root.dataset_id
+						user_properties = {
+							"com.example:share" = "yes"
+						}
+
+						depends_on = [truenas_dataset.child]
+					}
+				`, testPoolName, name, testPoolName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "datasets.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "datasets.0.dataset_id", fmt.Sprintf("%s/%s/child", testPoolName, name)),
+					resource.TestCheckResourceAttr(resourceName, "datasets.0.type", "FILESYSTEM"),
+					resource.TestCheckResourceAttrSet(resourceName, "datasets.0.used_bytes"),
+				),
+			},
+		},
+	})
+}
+
+func Test_filterDatasets(t *testing.T) {
+	encrypted := true
+	locked := true
+
+	datasets := flattenDatasetTree([]api.Dataset{
+		{Id: "Tank", Type: "FILESYSTEM"},
+		{Id: "Tank/apps", Type: "FILESYSTEM", AdditionalProperties: map[string]interface{}{
+			"children": []interface{}{
+				map[string]interface{}{"id": "Tank/apps/plex", "type": "FILESYSTEM", "user_properties": map[string]interface{}{
+					"com.example:share": map[string]interface{}{"value": "yes", "source": "LOCAL"},
+				}},
+			},
+		}},
+		{Id: "Tank/apps/plex", Type: "FILESYSTEM", AdditionalProperties: map[string]interface{}{
+			"user_properties": map[string]interface{}{
+				"com.example:share": map[string]interface{}{"value": "yes", "source": "LOCAL"},
+			},
+		}},
+		{Id: "Tank/apps/vm", Type: "VOLUME", Encrypted: &encrypted, Locked: &locked},
+		{Id: "Tank/apps2", Type: "FILESYSTEM"},
+	})
+
+	ids := func(list []api.Dataset) []string {
+		result := []string{}
+		for _, ds := range list {
+			result = append(result, ds.Id)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Tank/apps/plex", "Tank/apps/vm"}, ids(filterDatasets(datasets, datasetFilter{root: "Tank/apps", recursive: true})))
+	assert.Equal(t, []string{"Tank/apps", "Tank/apps/plex", "Tank/apps/vm"}, ids(filterDatasets(datasets, datasetFilter{root: "Tank/apps", includeRoot: true, recursive: true})))
+	assert.Equal(t, []string{"Tank/apps", "Tank/apps2"}, ids(filterDatasets(datasets, datasetFilter{root: "Tank"})))
+	assert.Equal(t, []string{"Tank/apps/vm"}, ids(filterDatasets(datasets, datasetFilter{root: "Tank", recursive: true, datasetType: "VOLUME"})))
+	assert.Equal(t, []string{"Tank/apps/vm"}, ids(filterDatasets(datasets, datasetFilter{root: "Tank", recursive: true, encryption: "locked"})))
+	assert.Equal(t, []string{"Tank/apps2"}, ids(filterDatasets(datasets, datasetFilter{root: "Tank", recursive: true, nameRegex: regexp.MustCompile(`2$`)})))
+	assert.Equal(t, []string{"Tank/apps/plex"}, ids(filterDatasets(datasets, datasetFilter{root: "Tank", recursive: true, userProperties: map[string]string{"com.example:share": "*"}})))
+}
+
+func Test_flattenDatasetsItem(t *testing.T) {
+	elem := dataSourceTrueNASDatasets().Schema["datasets"].Elem.(*schema.Resource)
+
+	item, err := flattenDatasetsItem(elem, &api.Dataset{
+		Id:          "Tank/apps/plex",
+		Type:        "FILESYSTEM",
+		Compression: &api.CompositeValue{Value: getStringPtr("LZ4"), Rawvalue: "lz4"},
+		Used:        &api.CompositeValue{Value: getStringPtr("1G"), Rawvalue: "1073741824"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Tank/apps/plex", item["dataset_id"])
+	assert.Equal(t, "apps", item["parent"])
+	assert.Equal(t, "lz4", item["compression"])
+	assert.Equal(t, 1073741824, item["used_bytes"])
+
+	// pool root dataset, returned with include_root
+	item, err = flattenDatasetsItem(elem, &api.Dataset{
+		Id:   "Tank",
+		Type: "FILESYSTEM",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Tank", item["dataset_id"])
+	assert.Equal(t, "Tank", item["pool"])
+	assert.Equal(t, "", item["parent"])
+	assert.Equal(t, "", item["name"])
+}
@@ -0,0 +1,277 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+type disk struct {
+	Identifier    string  `json:"identifier"`
+	Name          string  `json:"name"`
+	Serial        string  `json:"serial"`
+	Size          int64   `json:"size"`
+	Type          string  `json:"type"`
+	Model         string  `json:"model"`
+	Rotationrate  *int    `json:"rotationrate"`
+	Description   string  `json:"description"`
+	Hddstandby    string  `json:"hddstandby"`
+	Advpowermgmt  string  `json:"advpowermgmt"`
+	Togglesmart   bool    `json:"togglesmart"`
+	Smartoptions  string  `json:"smartoptions"`
+	Critical      *int    `json:"critical"`
+	Difference    *int    `json:"difference"`
+	Informational *int    `json:"informational"`
+	Expiretime    *string `json:"expiretime"`
+}
+
+func dataSourceTrueNASDisks() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get disks attached to TrueNAS and the pool each of them belongs to",
+		ReadContext: dataSourceTrueNASDisksRead,
+		Schema: map[string]*schema.Schema{
+			"type": &schema.Schema{
+				Description:  "Only return disks of this type: `HDD` or `SSD`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"HDD", "SSD"}, false),
+			},
+			"name_regex": &schema.Schema{
+				Description:  "Only return disks with names matching this regular expression, eg. `^da`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"min_size": &schema.Schema{
+				Description: "Only return disks of at least this size in bytes",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"unused": &schema.Schema{
+				Description: "Only return disks that are not part of any imported pool, boot pool included",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"disks": &schema.Schema{
+				Description: "Disks",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"identifier": &schema.Schema{
+							Description: "Stable disk identifier, eg. `{serial}WD-WCC4N0123456`",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": &schema.Schema{
+							Description: "Device name, eg. `ada0` or `sda`",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"serial": &schema.Schema{
+							Description: "Serial number",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"size": &schema.Schema{
+							Description: "Size in bytes",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"type": &schema.Schema{
+							Description: "Disk type: `HDD` or `SSD`",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"model": &schema.Schema{
+							Description: "Disk model",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"rotationrate": &schema.Schema{
+							Description: "Rotation rate in RPM, `0` for SSDs",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"pool": &schema.Schema{
+							Description: "Name of the pool disk belongs to, eg. `boot-pool` for boot devices, empty if disk is unused",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"unused": &schema.Schema{
+							Description: "`true` if disk is not part of any imported pool",
+							Type:        schema.TypeBool,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTrueNASDisksRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var disks []disk
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/disk", nil, &disks)
+
+	if err != nil {
+		return diag.Errorf("error getting disks: %s", err)
+	}
+
+	pools, err := getDiskPools(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filter := diskFilter{
+		diskType: d.Get("type").(string),
+		minSize:  int64(d.Get("min_size").(int)),
+		unused:   d.Get("unused").(bool),
+	}
+
+	if re, ok := d.GetOk("name_regex"); ok {
+		filter.nameRegex = regexp.MustCompile(re.(string))
+	}
+
+	if err := d.Set("disks", flattenDisks(filterDisks(disks, pools, filter), pools)); err != nil {
+		return diag.Errorf("error setting disks: %s", err)
+	}
+
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return diags
+}
+
+// getDiskPools returns map of disk names to name of the pool they belong to
+func getDiskPools(ctx context.Context, c *api.APIClient) (map[string]string, error) {
+	pools, _, err := c.PoolApi.ListPools(ctx).Execute()
+
+	if err != nil {
+		var body []byte
+		if apiErr, ok := err.(*api.GenericOpenAPIError); ok {
+			body = apiErr.Body()
+		}
+		return nil, fmt.Errorf("error getting pools: %s\n%s", err, body)
+	}
+
+	result := map[string]string{}
+
+	for _, pool := range pools {
+		var names []string
+
+		_, err := callAPI(ctx, c, http.MethodPost, fmt.Sprintf("/pool/id/%d/get_disks", pool.Id), nil, &names)
+
+		if err != nil {
+			return nil, fmt.Errorf("error getting disks of pool %s: %s", pool.Name, err)
+		}
+
+		for _, name := range names {
+			result[name] = pool.Name
+		}
+	}
+
+	// boot pool is not listed with other pools, its disks must not be reported as unused
+	var boot struct {
+		Name string `json:"name"`
+	}
+
+	_, err = callAPI(ctx, c, http.MethodGet, "/boot/get_state", nil, &boot)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting boot pool: %s", err)
+	}
+
+	var names []string
+
+	_, err = callAPI(ctx, c, http.MethodGet, "/boot/get_disks", nil, &names)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting disks of boot pool: %s", err)
+	}
+
+	for _, name := range names {
+		result[name] = boot.Name
+	}
+
+	return result, nil
+}
+
+type diskFilter struct {
+	diskType  string
+	nameRegex *regexp.Regexp
+	minSize   int64
+	unused    bool
+}
+
+func filterDisks(disks []disk, pools map[string]string, f diskFilter) []disk {
+	result := make([]disk, 0, len(disks))
+
+	for _, dsk := range disks {
+		// expired disks are no longer attached
+		if dsk.Expiretime != nil {
+			continue
+		}
+
+		if f.diskType != "" && dsk.Type != f.diskType {
+			continue
+		}
+
+		if f.nameRegex != nil && !f.nameRegex.MatchString(dsk.Name) {
+			continue
+		}
+
+		if dsk.Size < f.minSize {
+			continue
+		}
+
+		if _, inPool := pools[dsk.Name]; f.unused && inPool {
+			continue
+		}
+
+		result = append(result, dsk)
+	}
+
+	return result
+}
+
+func flattenDisks(disks []disk, pools map[string]string) []interface{} {
+	result := make([]interface{}, 0, len(disks))
+
+	for _, dsk := range disks {
+		rotationrate := 0
+
+		if dsk.Rotationrate != nil {
+			rotationrate = *dsk.Rotationrate
+		}
+
+		pool, inPool := pools[dsk.Name]
+
+		result = append(result, map[string]interface{}{
+			"identifier":   dsk.Identifier,
+			"name":         dsk.Name,
+			"serial":       dsk.Serial,
+			"size":         int(dsk.Size),
+			"type":         dsk.Type,
+			"model":        dsk.Model,
+			"rotationrate": rotationrate,
+			"pool":         pool,
+			"unused":       !inPool,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,101 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestAccDataSourceTruenasDisks_basic(t *testing.T) {
+	resourceName := "data.truenas_disks.all"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "truenas_disks" "all" {}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "disks.#"),
+					resource.TestCheckResourceAttrSet(resourceName, "disks.0.identifier"),
+					resource.TestCheckResourceAttrSet(resourceName, "disks.0.name"),
+				),
+			},
+		},
+	})
+}
+
+func Test_getDiskPools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2.0/pool":
+			fmt.Fprint(w, `[{"id": 1, "name": "Tank", "guid": "1", "path": "/mnt/Tank", "status": "ONLINE"}]`)
+		case "/api/v2.0/pool/id/1/get_disks":
+			fmt.Fprint(w, `["ada0", "ada1"]`)
+		case "/api/v2.0/boot/get_state":
+			fmt.Fprint(w, `{"name": "boot-pool", "status": "ONLINE"}`)
+		case "/api/v2.0/boot/get_disks":
+			fmt.Fprint(w, `["nvd0"]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	pools, err := getDiskPools(context.Background(), newTestAPIClient(server.URL+"/api/v2.0"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ada0": "Tank", "ada1": "Tank", "nvd0": "boot-pool"}, pools)
+}
+
+func Test_filterDisks(t *testing.T) {
+	expired := "2022-01-01"
+	disks := []disk{
+		{Identifier: "{serial}A", Name: "ada0", Type: "HDD", Size: 4000000000000},
+		{Identifier: "{serial}B", Name: "ada1", Type: "HDD", Size: 4000000000000},
+		{Identifier: "{serial}C", Name: "nvd0", Type: "SSD", Size: 500000000000},
+		{Identifier: "{serial}D", Name: "ada2", Type: "HDD", Size: 4000000000000, Expiretime: &expired},
+		{Identifier: "{serial}E", Name: "ada3", Type: "SSD", Size: 32000000000},
+	}
+	pools := map[string]string{"ada0": testPoolName, "ada3": "boot-pool"}
+
+	assert.Len(t, filterDisks(disks, pools, diskFilter{}), 4)
+	assert.Len(t, filterDisks(disks, pools, diskFilter{diskType: "HDD"}), 2)
+	assert.Len(t, filterDisks(disks, pools, diskFilter{minSize: 1000000000000}), 2)
+	assert.Equal(t, "{serial}C", filterDisks(disks, pools, diskFilter{nameRegex: regexp.MustCompile("^nvd")})[0].Identifier)
+
+	unused := filterDisks(disks, pools, diskFilter{diskType: "HDD", unused: true})
+
+	assert.Len(t, unused, 1)
+	assert.Equal(t, "{serial}B", unused[0].Identifier)
+
+	// boot devices are never reported as unused
+	unused = filterDisks(disks, pools, diskFilter{diskType: "SSD", unused: true})
+
+	assert.Len(t, unused, 1)
+	assert.Equal(t, "{serial}C", unused[0].Identifier)
+}
+
+func Test_flattenDisks(t *testing.T) {
+	rpm := 7200
+	disks := []disk{
+		{Identifier: "{serial}A", Name: "ada0", Type: "HDD", Rotationrate: &rpm},
+		{Identifier: "{serial}C", Name: "nvd0", Type: "SSD"},
+	}
+
+	result := flattenDisks(disks, map[string]string{"ada0": "tank"})
+
+	assert.Equal(t, "tank", result[0].(map[string]interface{})["pool"])
+	assert.Equal(t, false, result[0].(map[string]interface{})["unused"])
+	assert.Equal(t, 7200, result[0].(map[string]interface{})["rotationrate"])
+	assert.Equal(t, "", result[1].(map[string]interface{})["pool"])
+	assert.Equal(t, true, result[1].(map[string]interface{})["unused"])
+	assert.Equal(t, 0, result[1].(map[string]interface{})["rotationrate"])
+}
@@ -12,7 +12,7 @@ import (
 func dataSourceTrueNASZVOL() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceTrueNASZVOLRead,
-		Schema: map[string]*schema.Schema{
+		Schema: withDatasetSpaceUsage(map[string]*schema.Schema{
 			"zvol_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
@@ -98,11 +98,19 @@ func dataSourceTrueNASZVOL() *schema.Resource {
 				Description: "Sets the data write synchronization. `inherit` takes the sync settings from the parent dataset, `standard` uses the settings that have been requested by the client software, `always` waits for data writes to complete, and `disabled` never waits for writes to complete.",
 				Computed:    true,
 			},
+			"user_properties": &schema.Schema{
+				Description: "Locally set ZFS user properties",
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"volsize": &schema.Schema{
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
-		},
+		}),
 	}
 }
 
@@ -231,6 +239,14 @@ func dataSourceTrueNASZVOLRead(ctx context.Context, d *schema.ResourceData, m in
 		d.Set("encrypted", *resp.Encrypted)
 	}
 
+	if err := d.Set("user_properties", flattenUserProperties(resp)); err != nil {
+		return diag.Errorf("error setting user_properties: %s", err)
+	}
+
+	if err := setDatasetSpaceUsage(resp, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId(resp.Id)
 
 	return diags
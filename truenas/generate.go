@@ -0,0 +1,626 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GenerateTypes lists resource types supported by Generate, in the order they are written
+var GenerateTypes = []string{"truenas_dataset", "truenas_zvol", "truenas_share_nfs", "truenas_share_smb", "truenas_cronjob", "truenas_vm"}
+
+// GenerateOptions control which objects Generate adopts
+type GenerateOptions struct {
+	// Root limits datasets and zvols to the given dataset and its children, shares and VMs to those
+	// using only paths under it, cronjobs are not tied to datasets and are always included. All objects
+	// are included if empty.
+	Root string
+	// Types limits generated resource types, all GenerateTypes are included if empty
+	Types []string
+}
+
+type generatedResource struct {
+	Type string
+	Name string
+	ID   string
+	Data *schema.ResourceData
+}
+
+type generator struct {
+	ctx       context.Context
+	client    *api.APIClient
+	provider  *schema.Provider
+	root      string
+	resources []*generatedResource
+	names     map[string]bool
+	// datasets by mount point and zvols by ID, used to replace paths with references
+	mountPoints map[string]*generatedResource
+	zvols       map[string]*generatedResource
+	datasets    map[string]*generatedResource
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// Generate writes Terraform configuration with import blocks for existing TrueNAS objects,
+// paths of shares and VM disks reference generated datasets and zvols instead of literal strings
+func Generate(ctx context.Context, c *api.APIClient, opts GenerateOptions, w io.Writer) error {
+	g := &generator{
+		ctx:         ctx,
+		client:      c,
+		provider:    Provider(),
+		root:        opts.Root,
+		names:       map[string]bool{},
+		mountPoints: map[string]*generatedResource{},
+		zvols:       map[string]*generatedResource{},
+		datasets:    map[string]*generatedResource{},
+	}
+
+	types := opts.Types
+
+	if len(types) == 0 {
+		types = GenerateTypes
+	}
+
+	for _, t := range types {
+		if !containsString(GenerateTypes, t) {
+			return fmt.Errorf("unsupported resource type %s, expected one of: %s", t, strings.Join(GenerateTypes, ", "))
+		}
+	}
+
+	for _, t := range GenerateTypes {
+		if !containsString(types, t) {
+			continue
+		}
+
+		ids, err := g.listIDs(t, opts.Root)
+
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := g.read(t, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	for i, r := range g.resources {
+		if i > 0 {
+			body.AppendNewline()
+		}
+
+		g.writeResource(body, r)
+		body.AppendNewline()
+
+		imp := body.AppendNewBlock("import", nil).Body()
+		imp.SetAttributeTraversal("to", hcl.Traversal{hcl.TraverseRoot{Name: r.Type}, hcl.TraverseAttr{Name: r.Name}})
+		imp.SetAttributeValue("id", cty.StringVal(r.ID))
+	}
+
+	_, err := w.Write(hclwrite.Format(f.Bytes()))
+
+	return err
+}
+
+// listIDs returns import IDs of existing objects of given resource type
+func (g *generator) listIDs(resourceType string, root string) ([]string, error) {
+	if resourceType == "truenas_dataset" || resourceType == "truenas_zvol" {
+		datasets, _, err := g.client.DatasetApi.ListDatasets(g.ctx).Execute()
+
+		if err != nil {
+			return nil, fmt.Errorf("error listing datasets: %s", err)
+		}
+
+		dsType := datasetType
+
+		if resourceType == "truenas_zvol" {
+			dsType = "VOLUME"
+		}
+
+		all := flattenDatasetTree(datasets)
+		roots := []string{root}
+
+		// all pools
+		if root == "" {
+			roots = []string{}
+
+			for _, ds := range all {
+				if !strings.Contains(ds.Id, "/") && !containsString(roots, ds.Id) {
+					roots = append(roots, ds.Id)
+				}
+			}
+
+			sort.Strings(roots)
+		}
+
+		ids := []string{}
+
+		for _, r := range roots {
+			for _, ds := range filterDatasets(all, datasetFilter{root: r, includeRoot: true, recursive: true, datasetType: dsType}) {
+				// pool root datasets and datasets used by TrueNAS itself can not be managed
+				if !strings.Contains(ds.Id, "/") || isSystemDataset(ds.Id) {
+					continue
+				}
+
+				ids = append(ids, ds.Id)
+			}
+		}
+
+		return ids, nil
+	}
+
+	paths := map[string]string{
+		"truenas_share_nfs": "/sharing/nfs",
+		"truenas_share_smb": "/sharing/smb",
+		"truenas_cronjob":   "/cronjob",
+		"truenas_vm":        "/vm",
+	}
+
+	var objects []struct {
+		ID int `json:"id"`
+	}
+
+	if _, err := callAPI(g.ctx, g.client, http.MethodGet, paths[resourceType], nil, &objects); err != nil {
+		return nil, fmt.Errorf("error listing %s: %s", resourceType, err)
+	}
+
+	ids := make([]string, 0, len(objects))
+
+	for _, o := range objects {
+		ids = append(ids, strconv.Itoa(o.ID))
+	}
+
+	return ids, nil
+}
+
+// isSystemDataset returns true for datasets TrueNAS creates for its own use, eg. Tank/.system or Tank/ix-applications
+func isSystemDataset(id string) bool {
+	for _, part := range strings.Split(id, "/")[1:] {
+		if strings.HasPrefix(part, ".") || part == "ix-applications" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// read populates resource data using the provider's own read function, so generated
+// configuration matches the state import produces
+func (g *generator) read(resourceType string, id string) error {
+	r := g.provider.ResourcesMap[resourceType]
+	d := r.Data(nil)
+	d.SetId(id)
+
+	if resourceType == "truenas_dataset" || resourceType == "truenas_zvol" {
+		// there is no configuration yet, adopt all user properties
+		d.Set("all_user_properties", true)
+	}
+
+	log.Printf("[DEBUG] Reading %s %s", resourceType, id)
+
+	if diags := r.ReadContext(g.ctx, d, g.client); diags.HasError() {
+		return fmt.Errorf("error reading %s %s: %s", resourceType, id, diags[0].Summary)
+	}
+
+	// removed in the meantime
+	if d.Id() == "" {
+		return nil
+	}
+
+	if g.root != "" && (resourceType == "truenas_share_nfs" || resourceType == "truenas_share_smb" || resourceType == "truenas_vm") {
+		if !pathsInRoot(referencedPaths(resourceType, d), g.root) {
+			log.Printf("[DEBUG] Skipping %s %s, it uses paths outside of %s", resourceType, id, g.root)
+			return nil
+		}
+	}
+
+	res := &generatedResource{
+		Type: resourceType,
+		Name: g.uniqueName(resourceType, generatedResourceName(resourceType, id, d)),
+		ID:   id,
+		Data: d,
+	}
+
+	switch resourceType {
+	case "truenas_dataset":
+		g.datasets[id] = res
+
+		if mp, ok := d.GetOk("mount_point"); ok {
+			g.mountPoints[mp.(string)] = res
+		}
+	case "truenas_zvol":
+		g.zvols[id] = res
+	}
+
+	g.resources = append(g.resources, res)
+
+	return nil
+}
+
+// referencedPaths returns dataset and zvol paths used by a share or VM
+func referencedPaths(resourceType string, d *schema.ResourceData) []string {
+	switch resourceType {
+	case "truenas_share_nfs":
+		return expandStrings(d.Get("paths").(*schema.Set).List())
+	case "truenas_share_smb":
+		return []string{d.Get("path").(string)}
+	case "truenas_vm":
+		paths := []string{}
+
+		for _, dev := range d.Get("device").(*schema.Set).List() {
+			attrs, _ := dev.(map[string]interface{})["attributes"].(map[string]interface{})
+
+			if path, ok := attrs["path"].(string); ok && path != "" {
+				paths = append(paths, path)
+			}
+		}
+
+		return paths
+	}
+
+	return nil
+}
+
+// pathsInRoot returns true if there are paths and all of them are within the root dataset,
+// eg. /mnt/Tank/shares/media or /dev/zvol/Tank/shares/disk0 for Tank/shares
+func pathsInRoot(paths []string, root string) bool {
+	if len(paths) == 0 {
+		return false
+	}
+
+	for _, path := range paths {
+		inRoot := false
+
+		for _, prefix := range []string{"/mnt/" + root, "/dev/zvol/" + root} {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				inRoot = true
+			}
+		}
+
+		if !inRoot {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generatedResourceName picks a readable resource name, eg. tank_media for Tank/media dataset
+func generatedResourceName(resourceType string, id string, d *schema.ResourceData) string {
+	name := id
+
+	switch resourceType {
+	case "truenas_share_smb", "truenas_vm":
+		name = d.Get("name").(string)
+	case "truenas_share_nfs":
+		if paths := d.Get("paths").(*schema.Set).List(); len(paths) > 0 {
+			sort.Slice(paths, func(i, j int) bool { return paths[i].(string) < paths[j].(string) })
+			name = strings.TrimPrefix(paths[0].(string), "/mnt/")
+		}
+	case "truenas_cronjob":
+		name = "cronjob_" + id
+	}
+
+	name = sanitizeResourceName(name)
+
+	// eg. SMB share named "---"
+	if name == "" {
+		name = sanitizeResourceName(id)
+	}
+
+	if !hclsyntax.ValidIdentifier(name) {
+		name = strings.TrimPrefix(resourceType, "truenas_") + "_" + name
+	}
+
+	return name
+}
+
+func sanitizeResourceName(name string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_-")
+}
+
+// uniqueName appends a number to names already used by the same resource type, eg. tank_media_2
+func (g *generator) uniqueName(resourceType string, name string) string {
+	result := name
+
+	for i := 2; g.names[resourceType+"."+result]; i++ {
+		result = fmt.Sprintf("%s_%d", name, i)
+	}
+
+	g.names[resourceType+"."+result] = true
+
+	return result
+}
+
+func (g *generator) writeResource(body *hclwrite.Body, r *generatedResource) {
+	block := body.AppendNewBlock("resource", []string{r.Type, r.Name}).Body()
+	s := g.provider.ResourcesMap[r.Type].Schema
+
+	values := map[string]interface{}{}
+
+	for k := range s {
+		// skip attributes read did not set, there is no configuration to apply defaults from
+		if v, ok := r.Data.GetOkExists(k); ok {
+			values[k] = v
+		}
+	}
+
+	// only locally set properties are written, inherited ones are left to TrueNAS
+	// unless omitting them would apply the schema default
+	var sources map[string]interface{}
+
+	if v, ok := values["property_sources"].(map[string]interface{}); ok {
+		sources = v
+	}
+
+	// identity goes first, parent references generated parent dataset
+	if r.Type == "truenas_dataset" || r.Type == "truenas_zvol" {
+		block.SetAttributeValue("pool", cty.StringVal(r.Data.Get("pool").(string)))
+
+		if parent := g.parentTokens(r); parent != nil {
+			block.SetAttributeRaw("parent", parent)
+		} else if p := r.Data.Get("parent").(string); p != "" {
+			block.SetAttributeValue("parent", cty.StringVal(p))
+		}
+
+		block.SetAttributeValue("name", cty.StringVal(r.Data.Get("name").(string)))
+
+		for _, k := range []string{"pool", "parent", "name", "all_user_properties"} {
+			delete(values, k)
+		}
+	}
+
+	g.writeBody(block, s, values, sources)
+}
+
+// parentTokens returns reference to generated parent dataset
+func (g *generator) parentTokens(r *generatedResource) hclwrite.Tokens {
+	p := newDatasetPath(r.ID)
+
+	if p.Parent == "" {
+		return nil
+	}
+
+	parent, ok := g.datasets[p.Pool+"/"+p.Parent]
+
+	if !ok {
+		return nil
+	}
+
+	name := resourceTraversal(parent, "name")
+
+	if parent.Data.Get("parent").(string) == "" {
+		return hclwrite.TokensForTraversal(name)
+	}
+
+	return templateTokens(resourceTraversal(parent, "parent"), "/", name)
+}
+
+// writeBody writes configurable attributes that differ from defaults, nested resources are written as blocks
+func (g *generator) writeBody(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}, sources map[string]interface{}) {
+	keys := make([]string, 0, len(values))
+
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		sch, ok := s[k]
+
+		if !ok || (!sch.Required && !sch.Optional) || sch.Sensitive {
+			continue
+		}
+
+		v := values[k]
+
+		if !sch.Required {
+			if isDefaultValue(sch, v) {
+				continue
+			}
+
+			if src, ok := sources[k]; ok && src != "LOCAL" {
+				// omitted attribute with a default would override the inherited value on first apply
+				if sch.Default != nil {
+					body.SetAttributeRaw(k, g.inheritedValueTokens(sch, k, v))
+				}
+
+				continue
+			}
+		}
+
+		if elem, ok := sch.Elem.(*schema.Resource); ok {
+			for _, item := range sortedValues(v) {
+				if m, ok := item.(map[string]interface{}); ok {
+					g.writeBody(body.AppendNewBlock(k, nil).Body(), elem.Schema, m, nil)
+				}
+			}
+
+			continue
+		}
+
+		body.SetAttributeRaw(k, g.valueTokens(v))
+	}
+}
+
+// inheritedValueTokens returns inherit if the attribute accepts it, live value otherwise
+func (g *generator) inheritedValueTokens(sch *schema.Schema, k string, v interface{}) hclwrite.Tokens {
+	if sch.ValidateFunc != nil {
+		if _, errs := sch.ValidateFunc("inherit", k); len(errs) == 0 {
+			return hclwrite.TokensForValue(cty.StringVal("inherit"))
+		}
+	}
+
+	return g.valueTokens(v)
+}
+
+func (g *generator) valueTokens(v interface{}) hclwrite.Tokens {
+	switch val := v.(type) {
+	case string:
+		if ref := g.pathTokens(val); ref != nil {
+			return ref
+		}
+
+		return hclwrite.TokensForValue(cty.StringVal(val))
+	case int:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(val)))
+	case float64:
+		return hclwrite.TokensForValue(cty.NumberFloatVal(val))
+	case bool:
+		return hclwrite.TokensForValue(cty.BoolVal(val))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+
+		for k := range val {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		attrs := make([]hclwrite.ObjectAttrTokens, 0, len(keys))
+
+		for _, k := range keys {
+			name := hclwrite.TokensForValue(cty.StringVal(k))
+
+			if hclsyntax.ValidIdentifier(k) {
+				name = hclwrite.TokensForIdentifier(k)
+			}
+
+			attrs = append(attrs, hclwrite.ObjectAttrTokens{Name: name, Value: g.valueTokens(val[k])})
+		}
+
+		return hclwrite.TokensForObject(attrs)
+	default:
+		items := sortedValues(v)
+		elems := make([]hclwrite.Tokens, 0, len(items))
+
+		for _, item := range items {
+			elems = append(elems, g.valueTokens(item))
+		}
+
+		return hclwrite.TokensForTuple(elems)
+	}
+}
+
+// pathTokens replaces dataset mount points and zvol device paths with references to generated resources
+func (g *generator) pathTokens(path string) hclwrite.Tokens {
+	if strings.HasPrefix(path, "/dev/zvol/") {
+		if zvol, ok := g.zvols[strings.TrimPrefix(path, "/dev/zvol/")]; ok {
+			return templateTokens("/dev/zvol/", resourceTraversal(zvol, "zvol_id"))
+		}
+
+		return nil
+	}
+
+	if !strings.HasPrefix(path, "/mnt/") {
+		return nil
+	}
+
+	// longest mount point containing the path
+	var match string
+
+	for mp := range g.mountPoints {
+		if (path == mp || strings.HasPrefix(path, mp+"/")) && len(mp) > len(match) {
+			match = mp
+		}
+	}
+
+	if match == "" {
+		return nil
+	}
+
+	ref := resourceTraversal(g.mountPoints[match], "mount_point")
+
+	if path == match {
+		return hclwrite.TokensForTraversal(ref)
+	}
+
+	return templateTokens(ref, strings.TrimPrefix(path, match))
+}
+
+func resourceTraversal(r *generatedResource, attr string) hcl.Traversal {
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: r.Type},
+		hcl.TraverseAttr{Name: r.Name},
+		hcl.TraverseAttr{Name: attr},
+	}
+}
+
+// templateTokens builds string template from literal strings and references, eg. "${truenas_dataset.media.mount_point}/movies"
+func templateTokens(parts ...interface{}) hclwrite.Tokens {
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)}}
+
+	for _, part := range parts {
+		switch p := part.(type) {
+		case string:
+			// reuse escaping of quoted string literals, without the quotes
+			lit := hclwrite.TokensForValue(cty.StringVal(p))
+			tokens = append(tokens, lit[1:len(lit)-1]...)
+		case hcl.Traversal:
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateInterp, Bytes: []byte(`${`)})
+			tokens = append(tokens, hclwrite.TokensForTraversal(p)...)
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateSeqEnd, Bytes: []byte(`}`)})
+		}
+	}
+
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)})
+}
+
+// isDefaultValue returns true if value does not have to be written to configuration
+func isDefaultValue(sch *schema.Schema, v interface{}) bool {
+	if sch.Default != nil {
+		return fmt.Sprint(sch.Default) == fmt.Sprint(v)
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case int:
+		return val == 0
+	case float64:
+		return val == 0
+	case bool:
+		return !val
+	case map[string]interface{}:
+		return len(val) == 0
+	default:
+		return len(sortedValues(v)) == 0
+	}
+}
+
+// sortedValues returns list or set items, sets are sorted to keep output stable
+func sortedValues(v interface{}) []interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		return val
+	case *schema.Set:
+		items := val.List()
+
+		sort.SliceStable(items, func(i, j int) bool {
+			return fmt.Sprint(items[i]) < fmt.Sprint(items[j])
+		})
+
+		return items
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package truenas
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var testGenerateDatasets = map[string]string{
+	"Tank": `{"id": "Tank", "name": "Tank", "pool": "Tank", "type": "FILESYSTEM", "mountpoint": "/mnt/Tank"}`,
+	"Tank/media": `{"id": "Tank/media", "name": "Tank/media", "pool": "Tank", "type": "FILESYSTEM", "mountpoint": "/mnt/Tank/media",
+		"compression": {"value": "LZ4", "rawvalue": "lz4", "source": "LOCAL"},
+		"user_properties": {"com.example:owner": {"value": "media", "source": "LOCAL"}}}`,
+	"Tank/media/movies": `{"id": "Tank/media/movies", "name": "Tank/media/movies", "pool": "Tank", "type": "FILESYSTEM", "mountpoint": "/mnt/Tank/media/movies",
+		"compression": {"value": "LZ4", "rawvalue": "lz4", "source": "INHERITED"}}`,
+	"Tank/.system": `{"id": "Tank/.system", "name": "Tank/.system", "pool": "Tank", "type": "FILESYSTEM", "mountpoint": "/mnt/Tank/.system"}`,
+	"Tank/vm": `{"id": "Tank/vm", "name": "Tank/vm", "pool": "Tank", "type": "VOLUME",
+		"volsize": {"value": "1G", "rawvalue": "1073741824", "source": "LOCAL"},
+		"volblocksize": {"value": "16K", "rawvalue": "16384", "source": "DEFAULT"},
+		"sync": {"value": "ALWAYS", "rawvalue": "always", "source": "INHERITED"},
+		"deduplication": {"value": "OFF", "rawvalue": "off", "source": "DEFAULT"}}`,
+}
+
+func Test_Generate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := "/" + strings.TrimLeft(strings.TrimPrefix(r.URL.Path, "/api/v2.0"), "/")
+
+		switch {
+		case path == "/pool/dataset":
+			datasets := []string{}
+			for _, ds := range testGenerateDatasets {
+				datasets = append(datasets, ds)
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(datasets, ","))
+		case strings.HasPrefix(path, "/pool/dataset/id/"):
+			if ds, ok := testGenerateDatasets[strings.TrimPrefix(path, "/pool/dataset/id/")]; ok {
+				fmt.Fprint(w, ds)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case path == "/sharing/nfs":
+			fmt.Fprint(w, `[{"id": 2}]`)
+		case path == "/sharing/nfs/id/2":
+			fmt.Fprint(w, `{"id": 2, "paths": ["/mnt/Tank/media", "/mnt/Other/data"], "alldirs": false, "ro": true, "quiet": false, "enabled": true}`)
+		case path == "/sharing/smb":
+			fmt.Fprint(w, `[{"id": 1}]`)
+		case path == "/sharing/smb/id/1":
+			fmt.Fprint(w, `{"id": 1, "path": "/mnt/Tank/media/movies/4k", "name": "4K Movies", "purpose": "NO_PRESET", "enabled": true,
+				"home": false, "timemachine": false, "ro": false, "browsable": true, "recyclebin": false, "shadowcopy": true, "guestok": false,
+				"aapl_name_mangling": false, "abe": false, "acl": true, "durablehandle": true, "streams": true, "fsrvp": false}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL + "/api/v2.0/")
+
+	var out bytes.Buffer
+
+	err := Generate(context.Background(), c, GenerateOptions{Types: []string{"truenas_dataset", "truenas_zvol", "truenas_share_nfs", "truenas_share_smb"}}, &out)
+
+	assert.NoError(t, err)
+
+	// ignore alignment of attributes
+	hcl := regexp.MustCompile(` +=`).ReplaceAllString(out.String(), " =")
+
+	assert.Contains(t, hcl, `resource "truenas_dataset" "tank_media" {`)
+	assert.Contains(t, hcl, `compression = "lz4"`)
+	assert.Contains(t, hcl, `"com.example:owner" = "media"`)
+	assert.Contains(t, hcl, `parent = truenas_dataset.tank_media.name`)
+	assert.NotContains(t, hcl, `.system`)
+	assert.Contains(t, hcl, `resource "truenas_zvol" "tank_vm" {`)
+	assert.Contains(t, hcl, `volsize = 1073741824`)
+	assert.Contains(t, hcl, `resource "truenas_share_nfs" "other_data" {`)
+	assert.Contains(t, hcl, `paths = ["/mnt/Other/data", truenas_dataset.tank_media.mount_point]`)
+	assert.Contains(t, hcl, `resource "truenas_share_smb" "share_smb_4k_movies" {`)
+	assert.Contains(t, hcl, `path = "${truenas_dataset.tank_media_movies.mount_point}/4k"`)
+	assert.Contains(t, hcl, "import {\n  to = truenas_dataset.tank_media_movies\n  id = \"Tank/media/movies\"\n}")
+	assert.Contains(t, hcl, "import {\n  to = truenas_share_smb.share_smb_4k_movies\n  id = \"1\"\n}")
+
+	// compression of movies is inherited and should not be written
+	assert.Equal(t, 1, strings.Count(hcl, "compression"))
+
+	// inherited zvol sync differs from the schema default, omitting it would set sync = standard
+	assert.Contains(t, hcl, `sync = "inherit"`)
+	assert.NotContains(t, hcl, "deduplication")
+
+	// shares using paths outside of root are skipped
+	out.Reset()
+
+	err = Generate(context.Background(), c, GenerateOptions{Root: "Tank/media", Types: []string{"truenas_dataset", "truenas_share_nfs", "truenas_share_smb"}}, &out)
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `resource "truenas_share_smb" "share_smb_4k_movies" {`)
+	assert.NotContains(t, out.String(), `truenas_share_nfs`)
+
+	err = Generate(context.Background(), c, GenerateOptions{Types: []string{"truenas_pool"}}, &out)
+
+	assert.Error(t, err)
+}
+
+func Test_isSystemDataset(t *testing.T) {
+	assert.True(t, isSystemDataset("Tank/ix-applications/releases"))
+	assert.True(t, isSystemDataset("Tank/.system"))
+	assert.False(t, isSystemDataset("Tank/media"))
+}
+
+func Test_generatedResourceName(t *testing.T) {
+	testcases := []struct {
+		resourceType string
+		id           string
+		raw          map[string]interface{}
+		expected     string
+	}{
+		{resourceType: "truenas_dataset", id: "Tank/media", raw: map[string]interface{}{}, expected: "tank_media"},
+		{resourceType: "truenas_dataset", id: "Tank/My Media.2", raw: map[string]interface{}{}, expected: "tank_my_media_2"},
+		{resourceType: "truenas_zvol", id: "1tank/vm", raw: map[string]interface{}{}, expected: "zvol_1tank_vm"},
+		{resourceType: "truenas_cronjob", id: "3", raw: map[string]interface{}{}, expected: "cronjob_3"},
+		{resourceType: "truenas_share_smb", id: "1", raw: map[string]interface{}{"name": "Time Machine"}, expected: "time_machine"},
+		{resourceType: "truenas_share_smb", id: "2", raw: map[string]interface{}{"name": "4K Movies"}, expected: "share_smb_4k_movies"},
+		{resourceType: "truenas_share_smb", id: "3", raw: map[string]interface{}{"name": "---"}, expected: "share_smb_3"},
+		{resourceType: "truenas_share_nfs", id: "4", raw: map[string]interface{}{"paths": []interface{}{"/mnt/Tank/b", "/mnt/Tank/a"}}, expected: "tank_a"},
+		{resourceType: "truenas_vm", id: "5", raw: map[string]interface{}{"name": "web-01"}, expected: "web-01"},
+	}
+
+	p := Provider()
+
+	for _, c := range testcases {
+		d := schema.TestResourceDataRaw(t, p.ResourcesMap[c.resourceType].Schema, c.raw)
+
+		assert.Equal(t, c.expected, generatedResourceName(c.resourceType, c.id, d), c.id)
+	}
+}
+
+func Test_uniqueName(t *testing.T) {
+	g := &generator{names: map[string]bool{}}
+
+	assert.Equal(t, "tank_media", g.uniqueName("truenas_dataset", "tank_media"))
+	assert.Equal(t, "tank_media_2", g.uniqueName("truenas_dataset", "tank_media"))
+	assert.Equal(t, "tank_media_3", g.uniqueName("truenas_dataset", "tank_media"))
+	// names are scoped by resource type
+	assert.Equal(t, "tank_media", g.uniqueName("truenas_share_nfs", "tank_media"))
+}
+
+func Test_pathsInRoot(t *testing.T) {
+	assert.True(t, pathsInRoot([]string{"/mnt/Tank/shares", "/mnt/Tank/shares/media"}, "Tank/shares"))
+	assert.True(t, pathsInRoot([]string{"/dev/zvol/Tank/shares/disk0"}, "Tank/shares"))
+	assert.False(t, pathsInRoot([]string{"/mnt/Tank/shares", "/mnt/Other/data"}, "Tank/shares"))
+	assert.False(t, pathsInRoot([]string{"/mnt/Tank/shares2"}, "Tank/shares"))
+	assert.False(t, pathsInRoot([]string{}, "Tank/shares"))
+}
@@ -1,5 +1,11 @@
 package truenas
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 func flattenInt64List(list []int64) []interface{} {
 	result := make([]interface{}, 0, len(list))
 	for _, num := range list {
@@ -60,3 +66,87 @@ func convertStringMap(v map[string]interface{}) map[string]string {
 	}
 	return m
 }
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAttributes splits free-form attributes returned by TrueNAS into public and secret ones.
+// TrueNAS returns defaults for attributes that were never set, so only configured keys
+// are kept, unless configuration is not known yet (import), then known secret keys are split out
+func splitAttributes(a map[string]interface{}, secretKeys []string, configuredAttrs map[string]interface{}, configuredSecrets map[string]interface{}, importing bool) (map[string]string, map[string]string) {
+	attrs := map[string]string{}
+	secrets := map[string]string{}
+
+	for k, v := range a {
+		if v == nil {
+			continue
+		}
+
+		value := flattenAttributeValue(v)
+
+		if importing {
+			if value == "" {
+				continue
+			}
+
+			if containsString(secretKeys, k) {
+				secrets[k] = value
+			} else {
+				attrs[k] = value
+			}
+
+			continue
+		}
+
+		if _, ok := configuredSecrets[k]; ok {
+			secrets[k] = value
+		} else if _, ok := configuredAttrs[k]; ok {
+			attrs[k] = value
+		}
+	}
+
+	return attrs, secrets
+}
+
+// flattenAttributeValue converts decoded JSON value to its string map representation,
+// lists are joined with commas
+func flattenAttributeValue(v interface{}) string {
+	switch val := v.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, 0, len(val))
+		for _, item := range val {
+			items = append(items, flattenAttributeValue(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// expandAttributeValue converts string map value to the type TrueNAS expects,
+// "true"/"false" are converted to booleans and values of intKeys to integers
+func expandAttributeValue(key string, value string, intKeys []string) interface{} {
+	if value == "true" {
+		return true
+	}
+
+	if value == "false" {
+		return false
+	}
+
+	if containsString(intKeys, key) {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+
+	return value
+}
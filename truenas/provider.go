@@ -33,16 +33,46 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"truenas_cronjob":   resourceTrueNASCronjob(),
-			"truenas_dataset":   resourceTrueNASDataset(),
-			"truenas_share_nfs": resourceTrueNASShareNFS(),
-			"truenas_share_smb": resourceTrueNASShareSMB(),
-			"truenas_zvol":      resourceTrueNASZVOL(),
-			"truenas_vm":        resourceTrueNASVM(),
+			"truenas_activedirectory":  resourceTrueNASActiveDirectory(),
+			"truenas_alert_classes":    resourceTrueNASAlertClasses(),
+			"truenas_alert_service":    resourceTrueNASAlertService(),
+			"truenas_app":              resourceTrueNASApp(),
+			"truenas_apps_config":      resourceTrueNASAppsConfig(),
+			"truenas_catalog":          resourceTrueNASCatalog(),
+			"truenas_cloud_credential": resourceTrueNASCloudCredential(),
+			"truenas_cloudsync_task":   resourceTrueNASCloudSyncTask(),
+			"truenas_cronjob":          resourceTrueNASCronjob(),
+			"truenas_dataset":          resourceTrueNASDataset(),
+			"truenas_disk":             resourceTrueNASDisk(),
+			"truenas_idmap":            resourceTrueNASIdmap(),
+			"truenas_init_script":      resourceTrueNASInitScript(),
+			"truenas_kerberos_keytab":  resourceTrueNASKerberosKeytab(),
+			"truenas_kerberos_realm":   resourceTrueNASKerberosRealm(),
+			"truenas_ldap":             resourceTrueNASLDAP(),
+			"truenas_mail_config":      resourceTrueNASMailConfig(),
+			"truenas_ntp_server":       resourceTrueNASNTPServer(),
+			"truenas_rsync_module":     resourceTrueNASRsyncModule(),
+			"truenas_rsync_task":       resourceTrueNASRsyncTask(),
+			"truenas_scrub_task":       resourceTrueNASScrubTask(),
+			"truenas_share_nfs":        resourceTrueNASShareNFS(),
+			"truenas_share_smb":        resourceTrueNASShareSMB(),
+			"truenas_smart_config":     resourceTrueNASSMARTConfig(),
+			"truenas_smart_test":       resourceTrueNASSMARTTest(),
+			"truenas_ssh_config":       resourceTrueNASSSHConfig(),
+			"truenas_ssh_connection":   resourceTrueNASSSHConnection(),
+			"truenas_ssh_keypair":      resourceTrueNASSSHKeypair(),
+			"truenas_static_host":      resourceTrueNASStaticHost(),
+			"truenas_tunable":          resourceTrueNASTunable(),
+			"truenas_zvol":             resourceTrueNASZVOL(),
+			"truenas_vm":               resourceTrueNASVM(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
+			"truenas_alerts":                dataSourceTrueNASAlerts(),
+			"truenas_catalog_items":         dataSourceTrueNASCatalogItems(),
 			"truenas_cronjob":               dataSourceTrueNASCronjob(),
 			"truenas_dataset":               dataSourceTrueNASDataset(),
+			"truenas_datasets":              dataSourceTrueNASDatasets(),
+			"truenas_disks":                 dataSourceTrueNASDisks(),
 			"truenas_network_configuration": dataSourceTrueNASNetworkConfiguration(),
 			"truenas_pool_ids":              dataSourceTrueNASPoolIDs(),
 			"truenas_service":               dataSourceTrueNASService(),
@@ -63,6 +93,12 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
+	c := NewAPIClient(ctx, baseURL, apiKey, debug)
+	return c, diags
+}
+
+// NewAPIClient creates TrueNAS API client authenticated with an API key, also used by the generate command
+func NewAPIClient(ctx context.Context, baseURL string, apiKey string, debug bool) *api.APIClient {
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: apiKey},
 	)
@@ -77,6 +113,5 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	config.Debug = debug
 	config.HTTPClient = tc
 
-	c := api.NewAPIClient(config)
-	return c, diags
+	return api.NewAPIClient(config)
 }
@@ -0,0 +1,327 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type activeDirectoryConfig struct {
+	ID                int     `json:"id,omitempty"`
+	Domainname        string  `json:"domainname"`
+	Bindname          string  `json:"bindname,omitempty"`
+	Bindpw            string  `json:"bindpw,omitempty"`
+	Site              *string `json:"site"`
+	KerberosRealm     *int    `json:"kerberos_realm"`
+	KerberosPrincipal string  `json:"kerberos_principal"`
+	Netbiosname       string  `json:"netbiosname,omitempty"`
+	Createcomputer    string  `json:"createcomputer"`
+	UseDefaultDomain  bool    `json:"use_default_domain"`
+	AllowTrustedDoms  bool    `json:"allow_trusted_doms"`
+	AllowDNSUpdates   bool    `json:"allow_dns_updates"`
+	Timeout           int     `json:"timeout"`
+	DNSTimeout        int     `json:"dns_timeout"`
+	Enable            bool    `json:"enable"`
+	JobID             *int    `json:"job_id,omitempty"`
+}
+
+func resourceTrueNASActiveDirectory() *schema.Resource {
+	return &schema.Resource{
+		Description: "Active Directory configuration, TrueNAS joins the domain when enabled. This is a singleton, " +
+			"destroying the resource disables Active Directory without leaving the domain.",
+		CreateContext: resourceTrueNASActiveDirectoryCreate,
+		ReadContext:   resourceTrueNASActiveDirectoryRead,
+		UpdateContext: resourceTrueNASActiveDirectoryUpdate,
+		DeleteContext: resourceTrueNASActiveDirectoryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"domainname": &schema.Schema{
+				Description: "Active Directory domain, eg. `ad.example.com`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"bindname": &schema.Schema{
+				Description: "Account used to join the domain, not required if `kerberos_principal` is set",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"bindpw": &schema.Schema{
+				Description: "Password of `bindname` account",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"site": &schema.Schema{
+				Description: "Active Directory site TrueNAS is in, detected automatically if not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"kerberos_realm": &schema.Schema{
+				Description: "Kerberos realm ID, see `truenas_kerberos_realm`, created automatically if not set",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+			},
+			"kerberos_principal": &schema.Schema{
+				Description: "Kerberos principal from a keytab used to join the domain instead of bind credentials",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"netbiosname": &schema.Schema{
+				Description: "NetBIOS name of TrueNAS, used as computer account name",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"createcomputer": &schema.Schema{
+				Description: "Organizational unit where computer account is created, eg. `Computers/Servers/NAS`",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"use_default_domain": &schema.Schema{
+				Description: "Remove domain name prefix from user and group names",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"allow_trusted_doms": &schema.Schema{
+				Description: "Allow users and groups from trusted domains, requires idmap configuration for each domain",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"allow_dns_updates": &schema.Schema{
+				Description: "Register TrueNAS IP addresses in Active Directory DNS",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"timeout": &schema.Schema{
+				Description: "Seconds before Active Directory connection attempt times out",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     60,
+			},
+			"dns_timeout": &schema.Schema{
+				Description: "Seconds before DNS query times out",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+			},
+			"idmap_backend": &schema.Schema{
+				Description:  "Idmap backend used for the Active Directory domain: " + strings.Join(idmapBackends, ", "),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(idmapBackends, false),
+			},
+			"enable": &schema.Schema{
+				Description: "Enable Active Directory and join the domain",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"state": &schema.Schema{
+				Description: "Active Directory service state reported by TrueNAS, eg. `HEALTHY`, `FAULTED`, `DISABLED`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASActiveDirectoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var cfg activeDirectoryConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/activedirectory", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting active directory config: %s", err)
+	}
+
+	d.Set("domainname", cfg.Domainname)
+	d.Set("bindname", cfg.Bindname)
+	d.Set("kerberos_principal", cfg.KerberosPrincipal)
+	d.Set("netbiosname", cfg.Netbiosname)
+	d.Set("createcomputer", cfg.Createcomputer)
+	d.Set("use_default_domain", cfg.UseDefaultDomain)
+	d.Set("allow_trusted_doms", cfg.AllowTrustedDoms)
+	d.Set("allow_dns_updates", cfg.AllowDNSUpdates)
+	d.Set("timeout", cfg.Timeout)
+	d.Set("dns_timeout", cfg.DNSTimeout)
+	d.Set("enable", cfg.Enable)
+
+	if cfg.Site != nil {
+		d.Set("site", *cfg.Site)
+	}
+
+	if cfg.KerberosRealm != nil {
+		d.Set("kerberos_realm", *cfg.KerberosRealm)
+	}
+
+	idmap, err := getIdmapDomain(ctx, c, idmapActiveDirectory)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("idmap_backend", idmap.IdmapBackend)
+
+	states, err := getDirectoryServicesState(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("state", states["activedirectory"])
+
+	return diags
+}
+
+func resourceTrueNASActiveDirectoryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	id, err := updateActiveDirectory(ctx, c, d, d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.Itoa(id))
+
+	log.Printf("[INFO] TrueNAS active directory (%s) configured", d.Id())
+
+	return resourceTrueNASActiveDirectoryRead(ctx, d, m)
+}
+
+func resourceTrueNASActiveDirectoryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := updateActiveDirectory(ctx, c, d, d.Timeout(schema.TimeoutUpdate))
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceTrueNASActiveDirectoryRead(ctx, d, m)
+}
+
+func resourceTrueNASActiveDirectoryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Disabling TrueNAS active directory")
+
+	var res activeDirectoryConfig
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/activedirectory", map[string]interface{}{"enable": false}, &res)
+
+	if err != nil {
+		return diag.Errorf("error disabling active directory: %s", err)
+	}
+
+	if res.JobID != nil {
+		if _, err := waitForJob(ctx, c, *res.JobID, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("error disabling active directory: %s", err)
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// updateActiveDirectory saves configuration and waits for domain join, returns config ID
+func updateActiveDirectory(ctx context.Context, c *api.APIClient, d *schema.ResourceData, timeout time.Duration) (int, error) {
+	if d.HasChange("idmap_backend") {
+		if backend, ok := d.GetOk("idmap_backend"); ok {
+			if err := updateIdmapBackend(ctx, c, idmapActiveDirectory, backend.(string)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	input := expandActiveDirectory(d)
+
+	log.Printf("[DEBUG] Updating TrueNAS active directory: %s", input.Domainname)
+
+	var res activeDirectoryConfig
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/activedirectory", input, &res)
+
+	if err != nil {
+		return 0, fmt.Errorf("error updating active directory: %s", err)
+	}
+
+	if res.JobID != nil {
+		log.Printf("[DEBUG] Waiting for active directory job %d", *res.JobID)
+
+		if _, err := waitForJob(ctx, c, *res.JobID, timeout); err != nil {
+			return 0, fmt.Errorf("error joining active directory: %s", err)
+		}
+	}
+
+	return res.ID, nil
+}
+
+func expandActiveDirectory(d *schema.ResourceData) activeDirectoryConfig {
+	cfg := activeDirectoryConfig{
+		Domainname:        d.Get("domainname").(string),
+		Bindname:          d.Get("bindname").(string),
+		Bindpw:            d.Get("bindpw").(string),
+		KerberosPrincipal: d.Get("kerberos_principal").(string),
+		Netbiosname:       d.Get("netbiosname").(string),
+		Createcomputer:    d.Get("createcomputer").(string),
+		UseDefaultDomain:  d.Get("use_default_domain").(bool),
+		AllowTrustedDoms:  d.Get("allow_trusted_doms").(bool),
+		AllowDNSUpdates:   d.Get("allow_dns_updates").(bool),
+		Timeout:           d.Get("timeout").(int),
+		DNSTimeout:        d.Get("dns_timeout").(int),
+		Enable:            d.Get("enable").(bool),
+	}
+
+	if site, ok := d.GetOk("site"); ok {
+		cfg.Site = getStringPtr(site.(string))
+	}
+
+	if realm, ok := d.GetOk("kerberos_realm"); ok {
+		r := realm.(int)
+		cfg.KerberosRealm = &r
+	}
+
+	return cfg
+}
+
+// getDirectoryServicesState returns state of each directory service, eg. {"activedirectory": "HEALTHY", "ldap": "DISABLED"}
+func getDirectoryServicesState(ctx context.Context, c *api.APIClient) (map[string]string, error) {
+	states := map[string]string{}
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/directoryservices/get_state", nil, &states)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting directory services state: %s", err)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,37 @@
+package truenas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandActiveDirectory(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASActiveDirectory().Schema, map[string]interface{}{
+		"domainname": "ad.example.com",
+		"bindname":   "Administrator",
+		"bindpw":     "secret",
+	})
+
+	cfg := expandActiveDirectory(d)
+
+	assert.Equal(t, "ad.example.com", cfg.Domainname)
+	assert.True(t, cfg.Enable)
+	assert.True(t, cfg.AllowDNSUpdates)
+	// site and realm are detected by TrueNAS when not set
+	assert.Nil(t, cfg.Site)
+	assert.Nil(t, cfg.KerberosRealm)
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASActiveDirectory().Schema, map[string]interface{}{
+		"domainname":         "ad.example.com",
+		"kerberos_principal": "NAS$@AD.EXAMPLE.COM",
+		"kerberos_realm":     2,
+		"site":               "Office",
+	})
+
+	cfg = expandActiveDirectory(d)
+
+	assert.Equal(t, "", cfg.Bindpw)
+	assert.Equal(t, "Office", *cfg.Site)
+	assert.Equal(t, 2, *cfg.KerberosRealm)
+}
@@ -0,0 +1,174 @@
+package truenas
+
+import (
+	"context"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var alertPolicies = []string{"IMMEDIATELY", "HOURLY", "DAILY", "NEVER"}
+
+type alertClassSettings struct {
+	Level  string `json:"level,omitempty"`
+	Policy string `json:"policy,omitempty"`
+}
+
+type alertClasses struct {
+	ID      int                           `json:"id,omitempty"`
+	Classes map[string]alertClassSettings `json:"classes"`
+}
+
+func resourceTrueNASAlertClasses() *schema.Resource {
+	return &schema.Resource{
+		Description: "Per alert class level and notification policy overrides. This is a singleton, classes that are not listed use TrueNAS defaults, " +
+			"destroying the resource resets all classes to their defaults.",
+		CreateContext: resourceTrueNASAlertClassesCreate,
+		ReadContext:   resourceTrueNASAlertClassesRead,
+		UpdateContext: resourceTrueNASAlertClassesUpdate,
+		DeleteContext: resourceTrueNASAlertClassesDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"class": &schema.Schema{
+				Description: "Alert class override",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Description: "Alert class name, eg. `ZpoolCapacityWarning`",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"level": &schema.Schema{
+							Description:  "Alert level: " + strings.Join(alertLevels, ", ") + ". Class default is used if not set",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice(alertLevels, false),
+						},
+						"policy": &schema.Schema{
+							Description:  "How often alert services are notified: " + strings.Join(alertPolicies, ", "),
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "IMMEDIATELY",
+							ValidateFunc: validation.StringInSlice(alertPolicies, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceTrueNASAlertClassesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var ac alertClasses
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/alertclasses", nil, &ac)
+
+	if err != nil {
+		return diag.Errorf("error getting alert classes: %s", err)
+	}
+
+	if err := d.Set("class", flattenAlertClasses(ac.Classes)); err != nil {
+		return diag.Errorf("error setting class: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASAlertClassesCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Updating TrueNAS alert classes")
+
+	var ac alertClasses
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/alertclasses", expandAlertClasses(d), &ac)
+
+	if err != nil {
+		return diag.Errorf("error updating alert classes: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(ac.ID))
+
+	return resourceTrueNASAlertClassesRead(ctx, d, m)
+}
+
+func resourceTrueNASAlertClassesUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/alertclasses", expandAlertClasses(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating alert classes: %s", err)
+	}
+
+	return resourceTrueNASAlertClassesRead(ctx, d, m)
+}
+
+func resourceTrueNASAlertClassesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Resetting TrueNAS alert classes to defaults")
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/alertclasses", alertClasses{Classes: map[string]alertClassSettings{}}, nil)
+
+	if err != nil {
+		return diag.Errorf("error resetting alert classes: %s", err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandAlertClasses(d *schema.ResourceData) alertClasses {
+	ac := alertClasses{
+		Classes: map[string]alertClassSettings{},
+	}
+
+	for _, v := range d.Get("class").(*schema.Set).List() {
+		class := v.(map[string]interface{})
+
+		ac.Classes[class["name"].(string)] = alertClassSettings{
+			Level:  class["level"].(string),
+			Policy: class["policy"].(string),
+		}
+	}
+
+	return ac
+}
+
+func flattenAlertClasses(classes map[string]alertClassSettings) []interface{} {
+	result := make([]interface{}, 0, len(classes))
+
+	for name, settings := range classes {
+		policy := settings.Policy
+
+		// TrueNAS only stores policy if it was changed
+		if policy == "" {
+			policy = "IMMEDIATELY"
+		}
+
+		result = append(result, map[string]interface{}{
+			"name":   name,
+			"level":  settings.Level,
+			"policy": policy,
+		})
+	}
+
+	return result
+}
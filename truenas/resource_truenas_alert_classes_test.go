@@ -0,0 +1,59 @@
+package truenas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccResourceTruenasAlertClasses_basic(t *testing.T) {
+	resourceName := "truenas_alert_classes.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasAlertClassesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "class.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "class.*", map[string]string{
+						"name":   "ZpoolCapacityWarning",
+						"level":  "CRITICAL",
+						"policy": "HOURLY",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "class.*", map[string]string{
+						"name":   "ScrubStarted",
+						"policy": "NEVER",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func Test_flattenAlertClasses(t *testing.T) {
+	classes := map[string]alertClassSettings{
+		"ScrubStarted": {Policy: "NEVER"},
+		"SMART":        {Level: "CRITICAL"},
+	}
+
+	assert.ElementsMatch(t, []interface{}{
+		map[string]interface{}{"name": "ScrubStarted", "level": "", "policy": "NEVER"},
+		map[string]interface{}{"name": "SMART", "level": "CRITICAL", "policy": "IMMEDIATELY"},
+	}, flattenAlertClasses(classes))
+}
+
+const testAccCheckResourceTruenasAlertClassesConfig = `
+	resource "truenas_alert_classes" "test" {
+		class {
+			name = "ZpoolCapacityWarning"
+			level = "CRITICAL"
+			policy = "HOURLY"
+		}
+		class {
+			name = "ScrubStarted"
+			policy = "NEVER"
+		}
+	}
+`
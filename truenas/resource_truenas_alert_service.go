@@ -0,0 +1,306 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var alertServiceTypes = []string{"AWSSNS", "InfluxDB", "Mail", "Mattermost", "OpsGenie", "PagerDuty", "Slack", "SNMPTrap", "Telegram", "VictorOps"}
+
+var alertLevels = []string{"INFO", "NOTICE", "WARNING", "ERROR", "CRITICAL", "ALERT", "EMERGENCY"}
+
+// alert service attributes that hold secrets, these are only accepted in secret_attributes
+var alertServiceSecretAttributes = []string{"api_key", "url", "bot_token", "password", "secret_access_key", "service_key", "v3_authkey", "v3_privkey", "community"}
+
+// alert service attributes that TrueNAS expects as integers
+var alertServiceIntAttributes = []string{"port"}
+
+// alert service attributes that TrueNAS expects as lists, values are comma separated
+var alertServiceListAttributes = []string{"chat_ids"}
+
+type alertService struct {
+	ID         int                    `json:"id,omitempty"`
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Level      string                 `json:"level"`
+	Enabled    bool                   `json:"enabled"`
+}
+
+func resourceTrueNASAlertService() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Alert services deliver TrueNAS alerts to external systems, eg. email, Slack or OpsGenie.",
+		CreateContext: resourceTrueNASAlertServiceCreate,
+		ReadContext:   resourceTrueNASAlertServiceRead,
+		UpdateContext: resourceTrueNASAlertServiceUpdate,
+		DeleteContext: resourceTrueNASAlertServiceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"alert_service_id": &schema.Schema{
+				Description: "Alert service ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"name": &schema.Schema{
+				Description: "Alert service name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"type": &schema.Schema{
+				Description:  "Alert service type, eg. `Mail`, `Slack`, `OpsGenie`, `PagerDuty`",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(alertServiceTypes, false),
+			},
+			"attributes": &schema.Schema{
+				Description:      "Type specific attributes that are not secret, eg. `email` for Mail or `api_url` for OpsGenie. List attributes (`chat_ids`) are comma separated",
+				Type:             schema.TypeMap,
+				Optional:         true,
+				ValidateDiagFunc: validateAlertServiceAttributes,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret_attributes": &schema.Schema{
+				Description: "Type specific secret attributes, eg. `url` for Slack webhooks or `api_key` for OpsGenie",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"level": &schema.Schema{
+				Description:  "Minimum alert level sent to this service: " + strings.Join(alertLevels, ", "),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "WARNING",
+				ValidateFunc: validation.StringInSlice(alertLevels, false),
+			},
+			"enabled": &schema.Schema{
+				Description: "`true` if alert service is enabled",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"test_on_apply": &schema.Schema{
+				Description: "Send a test alert with the new configuration before it is saved, apply fails if it can't be delivered",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceTrueNASAlertServiceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var svc alertService
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/alertservice/id/%d", id), nil, &svc)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting alert service: %s", err)
+	}
+
+	// name is only unknown when resource is being imported
+	importing := d.Get("name").(string) == ""
+
+	d.Set("alert_service_id", svc.ID)
+	d.Set("name", svc.Name)
+	d.Set("type", svc.Type)
+	d.Set("level", svc.Level)
+	d.Set("enabled", svc.Enabled)
+
+	attrs, secrets := splitAttributes(
+		svc.Attributes,
+		alertServiceSecretAttributes,
+		d.Get("attributes").(map[string]interface{}),
+		d.Get("secret_attributes").(map[string]interface{}),
+		importing,
+	)
+
+	if err := d.Set("attributes", attrs); err != nil {
+		return diag.Errorf("error setting attributes: %s", err)
+	}
+
+	if err := d.Set("secret_attributes", secrets); err != nil {
+		return diag.Errorf("error setting secret_attributes: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASAlertServiceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandAlertService(d)
+
+	if d.Get("test_on_apply").(bool) {
+		if err := testAlertService(ctx, c, input); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[DEBUG] Creating TrueNAS alert service: %s", input.Name)
+
+	var svc alertService
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/alertservice", input, &svc)
+
+	if err != nil {
+		return diag.Errorf("error creating alert service: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(svc.ID))
+
+	log.Printf("[INFO] TrueNAS alert service (%s) created", d.Id())
+
+	return resourceTrueNASAlertServiceRead(ctx, d, m)
+}
+
+func resourceTrueNASAlertServiceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandAlertService(d)
+
+	// toggling test_on_apply alone does not change anything on TrueNAS
+	if d.Get("test_on_apply").(bool) && d.HasChangeExcept("test_on_apply") {
+		if err := testAlertService(ctx, c, input); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/alertservice/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating alert service: %s", err)
+	}
+
+	return resourceTrueNASAlertServiceRead(ctx, d, m)
+}
+
+func resourceTrueNASAlertServiceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS alert service: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/alertservice/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting alert service: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS alert service (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+// testAlertService sends a test alert using alertservice.test, TrueNAS responds with false
+// if alert could not be delivered
+func testAlertService(ctx context.Context, c *api.APIClient, svc alertService) error {
+	var ok bool
+
+	log.Printf("[DEBUG] Testing TrueNAS alert service: %s", svc.Name)
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/alertservice/test", svc, &ok)
+
+	if err != nil {
+		return fmt.Errorf("error testing alert service: %s", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("alert service %q test failed, check TrueNAS logs for details", svc.Name)
+	}
+
+	return nil
+}
+
+func expandAlertService(d *schema.ResourceData) alertService {
+	svc := alertService{
+		Name:       d.Get("name").(string),
+		Type:       d.Get("type").(string),
+		Level:      d.Get("level").(string),
+		Enabled:    d.Get("enabled").(bool),
+		Attributes: map[string]interface{}{},
+	}
+
+	for k, v := range d.Get("attributes").(map[string]interface{}) {
+		svc.Attributes[k] = expandAlertServiceAttribute(k, v.(string))
+	}
+
+	for k, v := range d.Get("secret_attributes").(map[string]interface{}) {
+		svc.Attributes[k] = expandAlertServiceAttribute(k, v.(string))
+	}
+
+	return svc
+}
+
+// expandAlertServiceAttribute converts string map value to the type TrueNAS expects
+func expandAlertServiceAttribute(key string, value string) interface{} {
+	if containsString(alertServiceListAttributes, key) {
+		items := []interface{}{}
+
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+
+			if item == "" {
+				continue
+			}
+
+			if i, err := strconv.Atoi(item); err == nil {
+				items = append(items, i)
+			} else {
+				items = append(items, item)
+			}
+		}
+
+		return items
+	}
+
+	return expandAttributeValue(key, value, alertServiceIntAttributes)
+}
+
+func validateAlertServiceAttributes(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for k := range v.(map[string]interface{}) {
+		if containsString(alertServiceSecretAttributes, k) {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("attribute %q holds a secret", k),
+				Detail:        fmt.Sprintf("attribute %q must be set in secret_attributes instead", k),
+				AttributePath: path,
+			})
+		}
+	}
+
+	return diags
+}
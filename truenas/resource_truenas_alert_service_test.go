@@ -0,0 +1,94 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestAccResourceTruenasAlertService_basic(t *testing.T) {
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	name := fmt.Sprintf("%s-%s", testResourcePrefix, suffix)
+	resourceName := "truenas_alert_service.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceTruenasAlertServiceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasAlertServiceConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "type", "Slack"),
+					resource.TestCheckResourceAttr(resourceName, "level", "ERROR"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "secret_attributes.url", "https://hooks.slack.com/services/T000/B000/XXXX"),
+					resource.TestCheckResourceAttrSet(resourceName, "alert_service_id"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"test_on_apply"},
+			},
+		},
+	})
+}
+
+func Test_expandAlertServiceAttribute(t *testing.T) {
+	assert.Equal(t, true, expandAlertServiceAttribute("v3", "true"))
+	assert.Equal(t, 162, expandAlertServiceAttribute("port", "162"))
+	assert.Equal(t, []interface{}{1234, 5678}, expandAlertServiceAttribute("chat_ids", "1234, 5678"))
+	assert.Equal(t, "ops@example.com", expandAlertServiceAttribute("email", "ops@example.com"))
+}
+
+func Test_flattenAttributeValue(t *testing.T) {
+	assert.Equal(t, "1234,5678", flattenAttributeValue([]interface{}{float64(1234), float64(5678)}))
+	assert.Equal(t, "1048576", flattenAttributeValue(float64(1048576)))
+	assert.Equal(t, "false", flattenAttributeValue(false))
+}
+
+func testAccCheckResourceTruenasAlertServiceConfig(name string) string {
+	return fmt.Sprintf(`
+	resource "truenas_alert_service" "test" {
+		name = "%s"
+		type = "Slack"
+		level = "ERROR"
+		enabled = false
+		secret_attributes = {
+			url = "https://hooks.slack.com/services/T000/B000/XXXX"
+		}
+	}
+	`, name)
+}
+
+func testAccCheckResourceTruenasAlertServiceDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api.APIClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "truenas_alert_service" {
+			continue
+		}
+
+		resp, err := callAPI(context.Background(), client, http.MethodGet, fmt.Sprintf("/alertservice/id/%s", rs.Primary.ID), nil, nil)
+
+		if err == nil {
+			return fmt.Errorf("alert service (%s) still exists", rs.Primary.ID)
+		}
+
+		// check if error is in fact 404 (not found)
+		if resp == nil || resp.StatusCode != 404 {
+			return fmt.Errorf("Error occured while checking for absence of alert service (%s)", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"gopkg.in/yaml.v3"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+type chartRelease struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Catalog       string `json:"catalog"`
+	CatalogTrain  string `json:"catalog_train"`
+	Status        string `json:"status"`
+	ChartMetadata struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"chart_metadata"`
+	PodStatus struct {
+		Available int `json:"available"`
+		Desired   int `json:"desired"`
+	} `json:"pod_status"`
+	UsedPorts []struct {
+		Port     int    `json:"port"`
+		Protocol string `json:"protocol"`
+	} `json:"used_ports"`
+}
+
+type chartReleaseCreate struct {
+	ReleaseName string                 `json:"release_name"`
+	Catalog     string                 `json:"catalog"`
+	Train       string                 `json:"train"`
+	Item        string                 `json:"item"`
+	Version     string                 `json:"version"`
+	Values      map[string]interface{} `json:"values"`
+}
+
+func resourceTrueNASApp() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Application (chart release) on TrueNAS SCALE. Changing `version` upgrades or rolls back the release.",
+		CreateContext: resourceTrueNASAppCreate,
+		ReadContext:   resourceTrueNASAppRead,
+		UpdateContext: resourceTrueNASAppUpdate,
+		DeleteContext: resourceTrueNASAppDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"release_name": &schema.Schema{
+				Description:  "Release name, must be a valid DNS label",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z]([-a-z0-9]{0,38}[a-z0-9])?$`), "must be a lowercase DNS label of at most 40 characters"),
+			},
+			"catalog": &schema.Schema{
+				Description: "Catalog label",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "OFFICIAL",
+			},
+			"train": &schema.Schema{
+				Description: "Catalog train",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "charts",
+			},
+			"item": &schema.Schema{
+				Description: "Catalog item (chart) name, eg. `plex`",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"version": &schema.Schema{
+				Description:  "Catalog item version, latest version is installed if not set. Higher version upgrades the release, lower version rolls it back.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateAppVersion,
+			},
+			"values": &schema.Schema{
+				Description:      "Chart values as a JSON or YAML document. Values are not read back from TrueNAS, as it adds defaults and secrets to them.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateAppValues,
+				DiffSuppressFunc: suppressEquivalentAppValues,
+			},
+			"rollback_snapshot": &schema.Schema{
+				Description: "Roll back snapshots of ix_volumes when rolling back to a lower version",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"status": &schema.Schema{
+				Description: "Release status, eg. `ACTIVE`, `DEPLOYING` or `STOPPED`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"pod_count": &schema.Schema{
+				Description: "Number of available pods",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"desired_pod_count": &schema.Schema{
+				Description: "Number of desired pods",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"used_ports": &schema.Schema{
+				Description: "Ports used by the release",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"port": &schema.Schema{
+							Description: "Port number",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"protocol": &schema.Schema{
+							Description: "Port protocol, eg. `TCP`",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceTrueNASAppRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var release chartRelease
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/chart/release/id/%s", d.Id()), nil, &release)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting app: %s", err)
+	}
+
+	d.Set("release_name", release.Name)
+	d.Set("catalog", release.Catalog)
+	d.Set("train", release.CatalogTrain)
+	d.Set("item", release.ChartMetadata.Name)
+	d.Set("version", release.ChartMetadata.Version)
+	d.Set("status", release.Status)
+	d.Set("pod_count", release.PodStatus.Available)
+	d.Set("desired_pod_count", release.PodStatus.Desired)
+
+	ports := make([]interface{}, 0, len(release.UsedPorts))
+
+	for _, p := range release.UsedPorts {
+		ports = append(ports, map[string]interface{}{
+			"port":     p.Port,
+			"protocol": p.Protocol,
+		})
+	}
+
+	if err := d.Set("used_ports", ports); err != nil {
+		return diag.Errorf("error setting used_ports: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASAppCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	values, err := expandAppValues(d.Get("values").(string))
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	input := chartReleaseCreate{
+		ReleaseName: d.Get("release_name").(string),
+		Catalog:     d.Get("catalog").(string),
+		Train:       d.Get("train").(string),
+		Item:        d.Get("item").(string),
+		Version:     "latest",
+		Values:      values,
+	}
+
+	if v, ok := d.GetOk("version"); ok {
+		input.Version = v.(string)
+	}
+
+	log.Printf("[DEBUG] Creating TrueNAS app: %s (%s %s)", input.ReleaseName, input.Item, input.Version)
+
+	if _, err := callJobAPI(ctx, c, http.MethodPost, "/chart/release", input, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("error creating app: %s", err)
+	}
+
+	d.SetId(input.ReleaseName)
+
+	log.Printf("[INFO] TrueNAS app (%s) created", d.Id())
+
+	return resourceTrueNASAppRead(ctx, d, m)
+}
+
+func resourceTrueNASAppUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	values, err := expandAppValues(d.Get("values").(string))
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	valuesApplied := false
+
+	if d.HasChange("version") {
+		o, n := d.GetChange("version")
+
+		cmp, err := compareAppVersions(o.(string), n.(string))
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if cmp < 0 {
+			log.Printf("[DEBUG] Upgrading TrueNAS app %s to %s", d.Id(), n)
+
+			input := map[string]interface{}{
+				"release_name": d.Id(),
+				"upgrade_options": map[string]interface{}{
+					"item_version": n,
+					"values":       values,
+				},
+			}
+
+			if _, err := callJobAPI(ctx, c, http.MethodPost, "/chart/release/upgrade", input, timeout); err != nil {
+				return diag.Errorf("error upgrading app: %s", err)
+			}
+
+			valuesApplied = true
+		} else if cmp > 0 {
+			log.Printf("[DEBUG] Rolling back TrueNAS app %s to %s", d.Id(), n)
+
+			input := map[string]interface{}{
+				"release_name": d.Id(),
+				"rollback_options": map[string]interface{}{
+					"item_version":      n,
+					"rollback_snapshot": d.Get("rollback_snapshot").(bool),
+				},
+			}
+
+			if _, err := callJobAPI(ctx, c, http.MethodPost, "/chart/release/rollback", input, timeout); err != nil {
+				return diag.Errorf("error rolling back app: %s", err)
+			}
+		}
+	}
+
+	// rollback restores values of the previous version, re-apply them if they changed too
+	if d.HasChange("values") && !valuesApplied {
+		input := map[string]interface{}{
+			"values": values,
+		}
+
+		if _, err := callJobAPI(ctx, c, http.MethodPut, fmt.Sprintf("/chart/release/id/%s", d.Id()), input, timeout); err != nil {
+			return diag.Errorf("error updating app: %s", err)
+		}
+	}
+
+	return resourceTrueNASAppRead(ctx, d, m)
+}
+
+func resourceTrueNASAppDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS app: %s", d.Id())
+
+	if _, err := callJobAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/chart/release/id/%s", d.Id()), nil, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.Errorf("error deleting app: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS app (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+// expandAppValues parses values document, JSON is a subset of YAML, so both are accepted
+func expandAppValues(doc string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+
+	if err := yaml.Unmarshal([]byte(doc), &values); err != nil {
+		return nil, fmt.Errorf("error parsing app values: %s", err)
+	}
+
+	// values have to be serializable to JSON, yaml allows non-string keys in nested maps
+	if _, err := json.Marshal(values); err != nil {
+		return nil, fmt.Errorf("error parsing app values: %s", err)
+	}
+
+	return values, nil
+}
+
+func validateAppValues(i interface{}, k string) ([]string, []error) {
+	if _, err := expandAppValues(i.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%q: %s", k, err)}
+	}
+
+	return nil, nil
+}
+
+func suppressEquivalentAppValues(k, old, new string, d *schema.ResourceData) bool {
+	o, err := expandAppValues(old)
+
+	if err != nil {
+		return false
+	}
+
+	n, err := expandAppValues(new)
+
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(o, n)
+}
+
+func validateAppVersion(i interface{}, k string) ([]string, []error) {
+	if _, err := version.NewVersion(i.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%q must be a semantic version: %s", k, err)}
+	}
+
+	return nil, nil
+}
+
+// compareAppVersions returns -1 if a is lower than b, 0 if equal and 1 if higher
+func compareAppVersions(a string, b string) (int, error) {
+	va, err := version.NewVersion(a)
+
+	if err != nil {
+		return 0, fmt.Errorf("error parsing app version %s: %s", a, err)
+	}
+
+	vb, err := version.NewVersion(b)
+
+	if err != nil {
+		return 0, fmt.Errorf("error parsing app version %s: %s", b, err)
+	}
+
+	return va.Compare(vb), nil
+}
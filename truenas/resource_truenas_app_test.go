@@ -0,0 +1,48 @@
+package truenas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandAppValues(t *testing.T) {
+	fromYAML, err := expandAppValues("timezone: Europe/Vilnius\nplexConfig:\n  claimToken: abc\n")
+
+	assert.NoError(t, err)
+
+	fromJSON, err := expandAppValues(`{"plexConfig": {"claimToken": "abc"}, "timezone": "Europe/Vilnius"}`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fromJSON, fromYAML)
+	assert.Equal(t, "abc", fromYAML["plexConfig"].(map[string]interface{})["claimToken"])
+
+	empty, err := expandAppValues("")
+
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = expandAppValues("just a string")
+
+	assert.Error(t, err)
+}
+
+func Test_suppressEquivalentAppValues(t *testing.T) {
+	assert.True(t, suppressEquivalentAppValues("values", `{"a": 1, "b": [1, 2]}`, "b: [1, 2]\na: 1\n", nil))
+	assert.False(t, suppressEquivalentAppValues("values", `{"a": 1}`, `{"a": 2}`, nil))
+}
+
+func Test_compareAppVersions(t *testing.T) {
+	cmp, err := compareAppVersions("1.6.9", "1.6.12")
+
+	assert.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	cmp, err = compareAppVersions("2.0.0", "1.10.0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	_, err = compareAppVersions("latest", "1.0.0")
+
+	assert.Error(t, err)
+}
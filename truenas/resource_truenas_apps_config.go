@@ -0,0 +1,148 @@
+package truenas
+
+import (
+	"context"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type appsConfig struct {
+	ID           int    `json:"id,omitempty"`
+	Pool         string `json:"pool"`
+	ClusterCIDR  string `json:"cluster_cidr,omitempty"`
+	ServiceCIDR  string `json:"service_cidr,omitempty"`
+	ClusterDNSIP string `json:"cluster_dns_ip,omitempty"`
+	NodeIP       string `json:"node_ip,omitempty"`
+}
+
+func resourceTrueNASAppsConfig() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Applications (kubernetes) configuration on TrueNAS SCALE. This is a singleton, destroying the resource only removes it from the state, apps keep running.",
+		CreateContext: resourceTrueNASAppsConfigCreate,
+		ReadContext:   resourceTrueNASAppsConfigRead,
+		UpdateContext: resourceTrueNASAppsConfigUpdate,
+		DeleteContext: resourceTrueNASAppsConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"pool": &schema.Schema{
+				Description: "Pool used to store applications, changing it migrates apps only if TrueNAS supports it",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"cluster_cidr": &schema.Schema{
+				Description:  "CIDR used for pod IPs, eg. `172.16.0.0/16`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsCIDR,
+			},
+			"service_cidr": &schema.Schema{
+				Description:  "CIDR used for service IPs, eg. `172.17.0.0/16`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsCIDR,
+			},
+			"cluster_dns_ip": &schema.Schema{
+				Description:  "Cluster DNS IP, must be within `service_cidr`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsIPAddress,
+			},
+			"node_ip": &schema.Schema{
+				Description:  "IP address of the node apps are bound to",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsIPAddress,
+			},
+		},
+	}
+}
+
+func resourceTrueNASAppsConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var cfg appsConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/kubernetes", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting apps config: %s", err)
+	}
+
+	d.Set("pool", cfg.Pool)
+	d.Set("cluster_cidr", cfg.ClusterCIDR)
+	d.Set("service_cidr", cfg.ServiceCIDR)
+	d.Set("cluster_dns_ip", cfg.ClusterDNSIP)
+	d.Set("node_ip", cfg.NodeIP)
+
+	return diags
+}
+
+func resourceTrueNASAppsConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Updating TrueNAS apps config")
+
+	if _, err := callJobAPI(ctx, c, http.MethodPut, "/kubernetes", expandAppsConfig(d), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("error updating apps config: %s", err)
+	}
+
+	var cfg appsConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/kubernetes", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting apps config: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(cfg.ID))
+
+	return resourceTrueNASAppsConfigRead(ctx, d, m)
+}
+
+func resourceTrueNASAppsConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	if _, err := callJobAPI(ctx, c, http.MethodPut, "/kubernetes", expandAppsConfig(d), d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("error updating apps config: %s", err)
+	}
+
+	return resourceTrueNASAppsConfigRead(ctx, d, m)
+}
+
+func resourceTrueNASAppsConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	log.Printf("[DEBUG] Removing TrueNAS apps config from state, settings are left unchanged")
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandAppsConfig(d *schema.ResourceData) appsConfig {
+	return appsConfig{
+		Pool:         d.Get("pool").(string),
+		ClusterCIDR:  d.Get("cluster_cidr").(string),
+		ServiceCIDR:  d.Get("service_cidr").(string),
+		ClusterDNSIP: d.Get("cluster_dns_ip").(string),
+		NodeIP:       d.Get("node_ip").(string),
+	}
+}
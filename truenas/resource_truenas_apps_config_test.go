@@ -0,0 +1,42 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandAppsConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASAppsConfig().Schema, map[string]interface{}{
+		"pool": "Tank",
+	})
+
+	cfg := expandAppsConfig(d)
+
+	assert.Equal(t, "Tank", cfg.Pool)
+
+	// networking is left to TrueNAS defaults when not configured
+	body, err := json.Marshal(cfg)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"pool": "Tank"}`, string(body))
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASAppsConfig().Schema, map[string]interface{}{
+		"pool":           "Apps",
+		"cluster_cidr":   "172.16.0.0/16",
+		"service_cidr":   "172.17.0.0/16",
+		"cluster_dns_ip": "172.17.0.10",
+		"node_ip":        "192.168.1.10",
+	})
+
+	cfg = expandAppsConfig(d)
+
+	assert.Equal(t, appsConfig{
+		Pool:         "Apps",
+		ClusterCIDR:  "172.16.0.0/16",
+		ServiceCIDR:  "172.17.0.0/16",
+		ClusterDNSIP: "172.17.0.10",
+		NodeIP:       "192.168.1.10",
+	}, cfg)
+}
@@ -0,0 +1,207 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type catalog struct {
+	ID              string   `json:"id,omitempty"`
+	Label           string   `json:"label"`
+	Repository      string   `json:"repository"`
+	Branch          string   `json:"branch"`
+	PreferredTrains []string `json:"preferred_trains,omitempty"`
+	Location        string   `json:"location,omitempty"`
+	Builtin         bool     `json:"builtin,omitempty"`
+}
+
+func resourceTrueNASCatalog() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Apps catalog on TrueNAS SCALE. Catalog is synced on creation and whenever preferred trains change.",
+		CreateContext: resourceTrueNASCatalogCreate,
+		ReadContext:   resourceTrueNASCatalogRead,
+		UpdateContext: resourceTrueNASCatalogUpdate,
+		DeleteContext: resourceTrueNASCatalogDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"label": &schema.Schema{
+				Description:      "Catalog label, TrueNAS stores it in upper case",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.StringMatch(regexp.MustCompile(`^\w+[\w.-]*$`), "must start with a letter, digit or underscore and contain only letters, digits, '_', '.' and '-'"),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+			"repository": &schema.Schema{
+				Description:  "Git repository URL",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https"}),
+			},
+			"branch": &schema.Schema{
+				Description: "Git branch",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "master",
+			},
+			"preferred_trains": &schema.Schema{
+				Description: "Trains shown in the UI and used when looking up apps",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"location": &schema.Schema{
+				Description: "Local path of the catalog clone",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+func resourceTrueNASCatalogRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var cat catalog
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/catalog/id/%s", d.Id()), nil, &cat)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting catalog: %s", err)
+	}
+
+	d.Set("label", cat.Label)
+	d.Set("repository", cat.Repository)
+	d.Set("branch", cat.Branch)
+	d.Set("location", cat.Location)
+
+	if err := d.Set("preferred_trains", cat.PreferredTrains); err != nil {
+		return diag.Errorf("error setting preferred_trains: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASCatalogCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := catalog{
+		Label:           d.Get("label").(string),
+		Repository:      d.Get("repository").(string),
+		Branch:          d.Get("branch").(string),
+		PreferredTrains: expandCatalogTrains(d),
+	}
+
+	log.Printf("[DEBUG] Creating TrueNAS catalog: %+v", input)
+
+	// catalog is cloned and synced within the create job
+	res, err := callJobAPI(ctx, c, http.MethodPost, "/catalog", input, d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		return diag.Errorf("error creating catalog: %s", err)
+	}
+
+	var cat catalog
+
+	if err := json.Unmarshal(res, &cat); err != nil || cat.ID == "" {
+		return diag.Errorf("error decoding created catalog: %s", res)
+	}
+
+	d.SetId(cat.ID)
+
+	log.Printf("[INFO] TrueNAS catalog (%s) created", d.Id())
+
+	return resourceTrueNASCatalogRead(ctx, d, m)
+}
+
+func resourceTrueNASCatalogUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	// everything else is ForceNew
+	input := map[string]interface{}{
+		"preferred_trains": expandCatalogTrains(d),
+	}
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/catalog/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating catalog: %s", err)
+	}
+
+	if err := syncCatalog(ctx, c, d.Id(), d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceTrueNASCatalogRead(ctx, d, m)
+}
+
+func resourceTrueNASCatalogDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS catalog: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/catalog/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting catalog: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS catalog (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func syncCatalog(ctx context.Context, c *api.APIClient, label string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Syncing TrueNAS catalog: %s", label)
+
+	if _, err := callJobAPI(ctx, c, http.MethodPost, "/catalog/sync", label, timeout); err != nil {
+		return fmt.Errorf("error syncing catalog %s: %s", label, err)
+	}
+
+	return nil
+}
+
+func expandCatalogTrains(d *schema.ResourceData) []string {
+	trains := []string{}
+
+	for _, t := range d.Get("preferred_trains").([]interface{}) {
+		trains = append(trains, t.(string))
+	}
+
+	return trains
+}
@@ -0,0 +1,78 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAccResourceTruenasCatalog_basic(t *testing.T) {
+	label := strings.ToUpper(fmt.Sprintf("%s_catalog", testResourcePrefix))
+	resourceName := "truenas_catalog.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceTruenasCatalogDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasCatalogConfig(label, "charts"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "label", label),
+					resource.TestCheckResourceAttr(resourceName, "preferred_trains.0", "charts"),
+					resource.TestCheckResourceAttrSet(resourceName, "location"),
+				),
+			},
+			{
+				Config: testAccCheckResourceTruenasCatalogConfig(label, "community"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "preferred_trains.0", "community"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckResourceTruenasCatalogConfig(label string, train string) string {
+	return fmt.Sprintf(`
+	resource "truenas_catalog" "test" {
+		label            = "%s"
+		repository       = "https://github.com/truenas/charts.git"
+		branch           = "master"
+		preferred_trains = ["%s"]
+	}
+	`, label, train)
+}
+
+func testAccCheckResourceTruenasCatalogDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api.APIClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "truenas_catalog" {
+			continue
+		}
+
+		resp, err := callAPI(context.Background(), client, http.MethodGet, fmt.Sprintf("/catalog/id/%s", rs.Primary.ID), nil, nil)
+
+		if err == nil {
+			return fmt.Errorf("catalog (%s) still exists", rs.Primary.ID)
+		}
+
+		// check if error is in fact 404 (not found)
+		if resp == nil || resp.StatusCode != 404 {
+			return fmt.Errorf("Error occured while checking for absence of catalog (%s)", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
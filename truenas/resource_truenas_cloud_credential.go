@@ -0,0 +1,227 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+var cloudProviders = []string{"AZUREBLOB", "B2", "BOX", "DROPBOX", "FTP", "GOOGLE_CLOUD_STORAGE", "GOOGLE_DRIVE", "GOOGLE_PHOTOS", "HTTP", "HUBIC", "MEGA", "ONEDRIVE", "OPENSTACK_SWIFT", "PCLOUD", "S3", "SFTP", "STORJ_IX", "WEBDAV", "YANDEX"}
+
+// provider attributes that hold secrets, these are only accepted in secret_attributes
+var cloudCredentialSecretAttributes = []string{"secret_access_key", "key", "client_secret", "token", "pass", "service_account_credentials", "password"}
+
+// provider attributes that TrueNAS expects as integers
+var cloudCredentialIntAttributes = []string{"port", "private_key", "max_upload_parts", "chunk_size"}
+
+type cloudCredential struct {
+	ID         int                    `json:"id,omitempty"`
+	Name       string                 `json:"name"`
+	Provider   string                 `json:"provider"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+func resourceTrueNASCloudCredential() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cloud credentials store the connection details (keys, tokens, passwords) TrueNAS uses to connect to remote cloud storage providers, they are used by Cloud Sync tasks.",
+		CreateContext: resourceTrueNASCloudCredentialCreate,
+		ReadContext:   resourceTrueNASCloudCredentialRead,
+		UpdateContext: resourceTrueNASCloudCredentialUpdate,
+		DeleteContext: resourceTrueNASCloudCredentialDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"credential_id": &schema.Schema{
+				Description: "Cloud credential ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"name": &schema.Schema{
+				Description: "Cloud credential name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"provider_type": &schema.Schema{
+				Description:  "Cloud storage provider type, eg. `S3`, `B2`, `AZUREBLOB`, `SFTP`",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(cloudProviders, false),
+			},
+			"attributes": &schema.Schema{
+				Description:      "Provider specific attributes that are not secret, eg. `access_key_id`, `endpoint`, `region` for S3",
+				Type:             schema.TypeMap,
+				Optional:         true,
+				ValidateDiagFunc: validateCloudCredentialAttributes,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret_attributes": &schema.Schema{
+				Description: "Provider specific secret attributes, eg. `secret_access_key` for S3, `pass` for FTP/SFTP/WEBDAV or `token` for OAuth based providers",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceTrueNASCloudCredentialRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var cred cloudCredential
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/cloudsync/credentials/id/%d", id), nil, &cred)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting cloud credential: %s", err)
+	}
+
+	// name is only unknown when resource is being imported
+	importing := d.Get("name").(string) == ""
+
+	d.Set("credential_id", cred.ID)
+	d.Set("name", cred.Name)
+	d.Set("provider_type", cred.Provider)
+
+	attrs, secrets := flattenCloudCredentialAttributes(
+		cred.Attributes,
+		d.Get("attributes").(map[string]interface{}),
+		d.Get("secret_attributes").(map[string]interface{}),
+		importing,
+	)
+
+	if err := d.Set("attributes", attrs); err != nil {
+		return diag.Errorf("error setting attributes: %s", err)
+	}
+
+	if err := d.Set("secret_attributes", secrets); err != nil {
+		return diag.Errorf("error setting secret_attributes: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASCloudCredentialCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandCloudCredential(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS cloud credential: %s", input.Name)
+
+	var cred cloudCredential
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/cloudsync/credentials", input, &cred)
+
+	if err != nil {
+		return diag.Errorf("error creating cloud credential: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(cred.ID))
+
+	log.Printf("[INFO] TrueNAS cloud credential (%s) created", d.Id())
+
+	return resourceTrueNASCloudCredentialRead(ctx, d, m)
+}
+
+func resourceTrueNASCloudCredentialUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandCloudCredential(d)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/cloudsync/credentials/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating cloud credential: %s", err)
+	}
+
+	return resourceTrueNASCloudCredentialRead(ctx, d, m)
+}
+
+func resourceTrueNASCloudCredentialDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS cloud credential: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/cloudsync/credentials/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting cloud credential: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS cloud credential (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandCloudCredential(d *schema.ResourceData) cloudCredential {
+	cred := cloudCredential{
+		Name:       d.Get("name").(string),
+		Provider:   d.Get("provider_type").(string),
+		Attributes: map[string]interface{}{},
+	}
+
+	for k, v := range d.Get("attributes").(map[string]interface{}) {
+		cred.Attributes[k] = expandCloudCredentialAttribute(k, v.(string))
+	}
+
+	for k, v := range d.Get("secret_attributes").(map[string]interface{}) {
+		cred.Attributes[k] = expandCloudCredentialAttribute(k, v.(string))
+	}
+
+	return cred
+}
+
+// expandCloudCredentialAttribute converts string map value to the type TrueNAS expects
+func expandCloudCredentialAttribute(key string, value string) interface{} {
+	return expandAttributeValue(key, value, cloudCredentialIntAttributes)
+}
+
+// flattenCloudCredentialAttributes splits attributes returned by TrueNAS into public and secret ones
+func flattenCloudCredentialAttributes(a map[string]interface{}, configuredAttrs map[string]interface{}, configuredSecrets map[string]interface{}, importing bool) (map[string]string, map[string]string) {
+	return splitAttributes(a, cloudCredentialSecretAttributes, configuredAttrs, configuredSecrets, importing)
+}
+
+func validateCloudCredentialAttributes(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for k := range v.(map[string]interface{}) {
+		if containsString(cloudCredentialSecretAttributes, k) {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("attribute %q holds a secret", k),
+				Detail:        fmt.Sprintf("attribute %q must be set in secret_attributes instead", k),
+				AttributePath: path,
+			})
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,477 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+type cloudSyncBandwidthLimit struct {
+	Time      string `json:"time"`
+	Bandwidth *int64 `json:"bandwidth"`
+}
+
+type cloudSyncTaskParams struct {
+	Description        string                    `json:"description"`
+	Path               string                    `json:"path"`
+	Credentials        int                       `json:"credentials"`
+	Attributes         map[string]interface{}    `json:"attributes"`
+	Direction          string                    `json:"direction"`
+	TransferMode       string                    `json:"transfer_mode"`
+	Encryption         bool                      `json:"encryption"`
+	FilenameEncryption bool                      `json:"filename_encryption"`
+	EncryptionPassword string                    `json:"encryption_password"`
+	EncryptionSalt     string                    `json:"encryption_salt"`
+	Include            []string                  `json:"include"`
+	Exclude            []string                  `json:"exclude"`
+	Bwlimit            []cloudSyncBandwidthLimit `json:"bwlimit"`
+	Schedule           *api.CronJobSchedule      `json:"schedule,omitempty"`
+	PreScript          string                    `json:"pre_script"`
+	PostScript         string                    `json:"post_script"`
+	Snapshot           bool                      `json:"snapshot"`
+	FollowSymlinks     bool                      `json:"follow_symlinks"`
+	Transfers          *int                      `json:"transfers,omitempty"`
+	Args               string                    `json:"args"`
+	Enabled            bool                      `json:"enabled"`
+}
+
+// cloudSyncTask is returned by TrueNAS, credentials are expanded into full object
+type cloudSyncTask struct {
+	ID                 int                       `json:"id"`
+	Description        string                    `json:"description"`
+	Path               string                    `json:"path"`
+	Credentials        cloudCredential           `json:"credentials"`
+	Attributes         map[string]interface{}    `json:"attributes"`
+	Direction          string                    `json:"direction"`
+	TransferMode       string                    `json:"transfer_mode"`
+	Encryption         bool                      `json:"encryption"`
+	FilenameEncryption bool                      `json:"filename_encryption"`
+	EncryptionPassword string                    `json:"encryption_password"`
+	EncryptionSalt     string                    `json:"encryption_salt"`
+	Include            []string                  `json:"include"`
+	Exclude            []string                  `json:"exclude"`
+	Bwlimit            []cloudSyncBandwidthLimit `json:"bwlimit"`
+	Schedule           api.CronJobSchedule       `json:"schedule"`
+	PreScript          string                    `json:"pre_script"`
+	PostScript         string                    `json:"post_script"`
+	Snapshot           bool                      `json:"snapshot"`
+	FollowSymlinks     bool                      `json:"follow_symlinks"`
+	Transfers          *int                      `json:"transfers"`
+	Args               string                    `json:"args"`
+	Enabled            bool                      `json:"enabled"`
+}
+
+func resourceTrueNASCloudSyncTask() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Cloud Sync tasks allow for single time transfers or recurring transfers of data between TrueNAS and cloud storage providers.",
+		CreateContext: resourceTrueNASCloudSyncTaskCreate,
+		ReadContext:   resourceTrueNASCloudSyncTaskRead,
+		UpdateContext: resourceTrueNASCloudSyncTaskUpdate,
+		DeleteContext: resourceTrueNASCloudSyncTaskDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"cloudsync_id": &schema.Schema{
+				Description: "Cloud sync task ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Cloud sync task description",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"path": &schema.Schema{
+				Description: "Local path to transfer, eg. `/mnt/Tank/backup`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"credentials": &schema.Schema{
+				Description: "Cloud credential ID",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+			"bucket": &schema.Schema{
+				Description: "Remote bucket (or container) name",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"folder": &schema.Schema{
+				Description: "Remote folder inside the bucket",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"attributes": &schema.Schema{
+				Description: "Additional provider specific task attributes, eg. `storage_class`, `encryption` or `fast_list` for S3",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"direction": &schema.Schema{
+				Description:  "`PUSH` sends data to cloud storage, `PULL` receives data from cloud storage",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"PUSH", "PULL"}, false),
+			},
+			"transfer_mode": &schema.Schema{
+				Description:  "`SYNC` makes files on destination identical to those on the source, `COPY` copies files from source to destination, `MOVE` copies files and deletes them from the source",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"SYNC", "COPY", "MOVE"}, false),
+			},
+			"encryption": &schema.Schema{
+				Description: "Use rclone crypt to manage data encryption during PUSH or PULL transfers",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"filename_encryption": &schema.Schema{
+				Description: "Encrypt (PUSH) or decrypt (PULL) file names with the rclone \"Standard\" file name encryption mode",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"encryption_password": &schema.Schema{
+				Description: "Password to encrypt and decrypt remote data",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"encryption_salt": &schema.Schema{
+				Description: "Encryption salt, used as a second password",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"include": &schema.Schema{
+				Description: "Only transfer files matching these patterns",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"exclude": &schema.Schema{
+				Description: "Exclude files matching these patterns",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"bwlimit": &schema.Schema{
+				Description: "Bandwidth limit schedule",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"time": &schema.Schema{
+							Description:  "Time the limit starts at, in `HH:MM` format",
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringMatch(regexp.MustCompile(`^([01]?[0-9]|2[0-3]):[0-5][0-9]$`), "time must be in HH:MM format"),
+						},
+						"bandwidth": &schema.Schema{
+							Description:  "Bandwidth limit in bytes per second, `0` for unlimited",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      0,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+					},
+				},
+			},
+			"schedule": resourceScheduleSchema("Cloud sync task schedule"),
+			"pre_script": &schema.Schema{
+				Description: "Script to execute before running sync",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"post_script": &schema.Schema{
+				Description: "Script to execute after running sync",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"snapshot": &schema.Schema{
+				Description: "Take a snapshot of the dataset before a PUSH",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"follow_symlinks": &schema.Schema{
+				Description: "Follow symlinks and copy the items to which they link",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"transfers": &schema.Schema{
+				Description:  "Number of simultaneous file transfers",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"args": &schema.Schema{
+				Description: "Additional rclone arguments",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"enabled": &schema.Schema{
+				Description: "`true` if cloud sync task is enabled",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASCloudSyncTaskRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var task cloudSyncTask
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/cloudsync/id/%d", id), nil, &task)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting cloud sync task: %s", err)
+	}
+
+	// path is only unknown when resource is being imported
+	importing := d.Get("path").(string) == ""
+
+	d.Set("cloudsync_id", task.ID)
+	d.Set("description", task.Description)
+	d.Set("path", task.Path)
+	d.Set("credentials", task.Credentials.ID)
+	d.Set("direction", task.Direction)
+	d.Set("transfer_mode", task.TransferMode)
+	d.Set("encryption", task.Encryption)
+	d.Set("filename_encryption", task.FilenameEncryption)
+	d.Set("encryption_password", task.EncryptionPassword)
+	d.Set("encryption_salt", task.EncryptionSalt)
+	d.Set("pre_script", task.PreScript)
+	d.Set("post_script", task.PostScript)
+	d.Set("snapshot", task.Snapshot)
+	d.Set("follow_symlinks", task.FollowSymlinks)
+	d.Set("args", task.Args)
+	d.Set("enabled", task.Enabled)
+
+	if task.Transfers != nil {
+		d.Set("transfers", *task.Transfers)
+	}
+
+	if bucket, ok := task.Attributes["bucket"]; ok && bucket != nil {
+		d.Set("bucket", fmt.Sprintf("%v", bucket))
+	}
+
+	if folder, ok := task.Attributes["folder"]; ok && folder != nil {
+		d.Set("folder", fmt.Sprintf("%v", folder))
+	}
+
+	if err := d.Set("attributes", flattenCloudSyncAttributes(task.Attributes, d.Get("attributes").(map[string]interface{}), importing)); err != nil {
+		return diag.Errorf("error setting attributes: %s", err)
+	}
+
+	if err := d.Set("include", flattenStringList(task.Include)); err != nil {
+		return diag.Errorf("error setting include: %s", err)
+	}
+
+	if err := d.Set("exclude", flattenStringList(task.Exclude)); err != nil {
+		return diag.Errorf("error setting exclude: %s", err)
+	}
+
+	if err := d.Set("bwlimit", flattenCloudSyncBandwidthLimits(task.Bwlimit)); err != nil {
+		return diag.Errorf("error setting bwlimit: %s", err)
+	}
+
+	if err := d.Set("schedule", flattenSchedule(task.Schedule)); err != nil {
+		return diag.Errorf("error setting schedule: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASCloudSyncTaskCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandCloudSyncTask(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS cloud sync task: %+v", input.Description)
+
+	var task cloudSyncTask
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/cloudsync", input, &task)
+
+	if err != nil {
+		return diag.Errorf("error creating cloud sync task: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(task.ID))
+
+	log.Printf("[INFO] TrueNAS cloud sync task (%s) created", d.Id())
+
+	return resourceTrueNASCloudSyncTaskRead(ctx, d, m)
+}
+
+func resourceTrueNASCloudSyncTaskUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandCloudSyncTask(d)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/cloudsync/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating cloud sync task: %s", err)
+	}
+
+	return resourceTrueNASCloudSyncTaskRead(ctx, d, m)
+}
+
+func resourceTrueNASCloudSyncTaskDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS cloud sync task: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/cloudsync/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting cloud sync task: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS cloud sync task (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandCloudSyncTask(d *schema.ResourceData) cloudSyncTaskParams {
+	task := cloudSyncTaskParams{
+		Description:        d.Get("description").(string),
+		Path:               d.Get("path").(string),
+		Credentials:        d.Get("credentials").(int),
+		Attributes:         map[string]interface{}{},
+		Direction:          d.Get("direction").(string),
+		TransferMode:       d.Get("transfer_mode").(string),
+		Encryption:         d.Get("encryption").(bool),
+		FilenameEncryption: d.Get("filename_encryption").(bool),
+		EncryptionPassword: d.Get("encryption_password").(string),
+		EncryptionSalt:     d.Get("encryption_salt").(string),
+		Include:            expandStrings(d.Get("include").([]interface{})),
+		Exclude:            expandStrings(d.Get("exclude").([]interface{})),
+		Bwlimit:            expandCloudSyncBandwidthLimits(d.Get("bwlimit").([]interface{})),
+		PreScript:          d.Get("pre_script").(string),
+		PostScript:         d.Get("post_script").(string),
+		Snapshot:           d.Get("snapshot").(bool),
+		FollowSymlinks:     d.Get("follow_symlinks").(bool),
+		Args:               d.Get("args").(string),
+		Enabled:            d.Get("enabled").(bool),
+	}
+
+	for k, v := range d.Get("attributes").(map[string]interface{}) {
+		task.Attributes[k] = expandCloudCredentialAttribute(k, v.(string))
+	}
+
+	if bucket, ok := d.GetOk("bucket"); ok {
+		task.Attributes["bucket"] = bucket.(string)
+	}
+
+	if folder, ok := d.GetOk("folder"); ok {
+		task.Attributes["folder"] = folder.(string)
+	}
+
+	if transfers, ok := d.GetOk("transfers"); ok {
+		t := transfers.(int)
+		task.Transfers = &t
+	}
+
+	if schedule, ok := d.GetOk("schedule"); ok {
+		task.Schedule = expandJobSchedule(schedule.([]interface{}))
+	}
+
+	return task
+}
+
+func expandCloudSyncBandwidthLimits(l []interface{}) []cloudSyncBandwidthLimit {
+	result := make([]cloudSyncBandwidthLimit, 0, len(l))
+
+	for _, item := range l {
+		lMap := item.(map[string]interface{})
+
+		limit := cloudSyncBandwidthLimit{
+			Time: lMap["time"].(string),
+		}
+
+		// 0 means unlimited, TrueNAS expects null in that case
+		if bandwidth := lMap["bandwidth"].(int); bandwidth > 0 {
+			limit.Bandwidth = getInt64Ptr(int64(bandwidth))
+		}
+
+		result = append(result, limit)
+	}
+
+	return result
+}
+
+func flattenCloudSyncBandwidthLimits(l []cloudSyncBandwidthLimit) []interface{} {
+	result := make([]interface{}, 0, len(l))
+
+	for _, limit := range l {
+		lMap := map[string]interface{}{
+			"time":      limit.Time,
+			"bandwidth": 0,
+		}
+
+		if limit.Bandwidth != nil {
+			lMap["bandwidth"] = int(*limit.Bandwidth)
+		}
+
+		result = append(result, lMap)
+	}
+
+	return result
+}
+
+// flattenCloudSyncAttributes returns additional task attributes, bucket and folder have their own fields.
+// TrueNAS fills in provider defaults, so only configured keys are kept unless resource is being imported
+func flattenCloudSyncAttributes(a map[string]interface{}, configured map[string]interface{}, importing bool) map[string]string {
+	result := map[string]string{}
+
+	for k, v := range a {
+		if v == nil || k == "bucket" || k == "folder" {
+			continue
+		}
+
+		value := flattenAttributeValue(v)
+
+		if _, ok := configured[k]; ok || (importing && value != "") {
+			result[k] = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package truenas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_cloudSyncBandwidthLimits(t *testing.T) {
+	limits := []interface{}{
+		map[string]interface{}{"time": "08:00", "bandwidth": 1048576},
+		map[string]interface{}{"time": "18:00", "bandwidth": 0},
+	}
+
+	expanded := expandCloudSyncBandwidthLimits(limits)
+
+	if assert.Len(t, expanded, 2) {
+		assert.Equal(t, "08:00", expanded[0].Time)
+		assert.Equal(t, int64(1048576), *expanded[0].Bandwidth)
+		assert.Equal(t, "18:00", expanded[1].Time)
+		assert.Nil(t, expanded[1].Bandwidth)
+	}
+
+	assert.Equal(t, limits, flattenCloudSyncBandwidthLimits(expanded))
+}
+
+func Test_flattenCloudSyncAttributes(t *testing.T) {
+	a := map[string]interface{}{
+		"bucket":        "backups",
+		"folder":        "nas",
+		"storage_class": "STANDARD_IA",
+		"fast_list":     false,
+		"encryption":    nil,
+		"chunk_size":    float64(10485760),
+		"acl":           []interface{}{"private", "bucket-owner-read"},
+	}
+
+	assert.Equal(t, map[string]string{"storage_class": "STANDARD_IA"}, flattenCloudSyncAttributes(a, map[string]interface{}{"storage_class": "STANDARD_IA"}, false))
+	assert.Equal(t, map[string]string{"storage_class": "STANDARD_IA", "fast_list": "false", "chunk_size": "10485760", "acl": "private,bucket-owner-read"}, flattenCloudSyncAttributes(a, map[string]interface{}{}, true))
+}
@@ -5,9 +5,17 @@ import (
 	api "github.com/dariusbakunas/truenas-go-sdk"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
 	"strconv"
 )
 
+// validateCommand is shared by resources that run shell commands (cronjobs, init scripts)
+var validateCommand = validation.StringIsNotWhiteSpace
+
+// validateScriptPath is shared by resources that run scripts stored on TrueNAS
+var validateScriptPath = validation.StringMatch(regexp.MustCompile(`^/\S+$`), "must be an absolute path without whitespace")
+
 func resourceTrueNASCronjob() *schema.Resource {
 	return &schema.Resource{
 		Description:   "TrueNAS allows users to run specific commands or scripts on a regular schedule using cron(8). This can be helpful for running repetitive tasks.",
@@ -30,9 +38,10 @@ func resourceTrueNASCronjob() *schema.Resource {
 				Required:    true,
 			},
 			"command": &schema.Schema{
-				Description: "Command or script that runs on schedule",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:  "Command or script that runs on schedule",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCommand,
 			},
 			"description": &schema.Schema{
 				Description: "Optional cronjob description",
@@ -57,41 +66,7 @@ func resourceTrueNASCronjob() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 			},
-			"schedule": &schema.Schema{
-				Description: "Cronjob schedule",
-				Type:        schema.TypeList,
-				Required:    true,
-				MaxItems:    1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"minute": &schema.Schema{
-							Type:     schema.TypeString,
-							Default:  "00",
-							Optional: true,
-						},
-						"hour": &schema.Schema{
-							Type:     schema.TypeString,
-							Default:  "*",
-							Optional: true,
-						},
-						"dom": &schema.Schema{
-							Type:     schema.TypeString,
-							Default:  "*",
-							Optional: true,
-						},
-						"month": &schema.Schema{
-							Type:     schema.TypeString,
-							Default:  "*",
-							Optional: true,
-						},
-						"dow": &schema.Schema{
-							Type:     schema.TypeString,
-							Default:  "*",
-							Optional: true,
-						},
-					},
-				},
-			},
+			"schedule": resourceScheduleSchema("Cronjob schedule"),
 		},
 	}
 }
@@ -257,7 +232,11 @@ func expandJobSchedule(s []interface{}) *api.CronJobSchedule {
 
 	mSchedule := s[0].(map[string]interface{})
 
-	schedule.Minute = getStringPtr(mSchedule["minute"].(string))
+	// hourly schedules have no minute field
+	if minute, ok := mSchedule["minute"]; ok {
+		schedule.Minute = getStringPtr(minute.(string))
+	}
+
 	schedule.Hour = getStringPtr(mSchedule["hour"].(string))
 	schedule.Dom = getStringPtr(mSchedule["dom"].(string))
 	schedule.Month = getStringPtr(mSchedule["month"].(string))
@@ -265,3 +244,51 @@ func expandJobSchedule(s []interface{}) *api.CronJobSchedule {
 
 	return schedule
 }
+
+// resourceScheduleSchema returns cron-like schedule block shared by
+// resources that run on a schedule (cronjobs, cloud sync tasks etc.)
+func resourceScheduleSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Description: description,
+		Type:        schema.TypeList,
+		Required:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"minute": &schema.Schema{
+					Type:     schema.TypeString,
+					Default:  "00",
+					Optional: true,
+				},
+				"hour": &schema.Schema{
+					Type:     schema.TypeString,
+					Default:  "*",
+					Optional: true,
+				},
+				"dom": &schema.Schema{
+					Type:     schema.TypeString,
+					Default:  "*",
+					Optional: true,
+				},
+				"month": &schema.Schema{
+					Type:     schema.TypeString,
+					Default:  "*",
+					Optional: true,
+				},
+				"dow": &schema.Schema{
+					Type:     schema.TypeString,
+					Default:  "*",
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+// resourceHourlyScheduleSchema returns schedule block without minute field,
+// used by tasks that TrueNAS only schedules with hourly granularity (S.M.A.R.T. tests)
+func resourceHourlyScheduleSchema(description string) *schema.Schema {
+	s := resourceScheduleSchema(description)
+	delete(s.Elem.(*schema.Resource).Schema, "minute")
+	return s
+}
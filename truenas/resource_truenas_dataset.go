@@ -8,7 +8,10 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"log"
+	"net/http"
+	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,12 +28,51 @@ type datasetPath struct {
 var supportedCompression = []string{"off", "lz4", "gzip", "gzip-1", "gzip-9", "zstd", "zstd-fast", "zle", "lzjb", "zstd-1", "zstd-2", "zstd-3", "zstd-4", "zstd-5", "zstd-6", "zstd-7", "zstd-8", "zstd-9", "zstd-10", "zstd-11", "zstd-12", "zstd-13", "zstd-14", "zstd-15", "zstd-16", "zstd-17", "zstd-18", "zstd-19", "zstd-fast-1", "zstd-fast-2", "zstd-fast-3", "zstd-fast-4", "zstd-fast-5", "zstd-fast-6", "zstd-fast-7", "zstd-fast-8", "zstd-fast-9", "zstd-fast-10", "zstd-fast-20", "zstd-fast-30", "zstd-fast-40", "zstd-fast-50", "zstd-fast-60", "zstd-fast-70", "zstd-fast-80", "zstd-fast-90", "zstd-fast-100", "zstd-fast-500", "zstd-fast-1000"}
 var encryptionAlgorithms = []string{"AES-128-CCM", "AES-192-CCM", "AES-256-CCM", "AES-128-GCM", "AES-192-GCM", "AES-256-GCM"}
 var recordSizes = []string{"512", "1K", "2K", "4K", "8K", "16K", "32K", "64K", "128K", "256K", "512K", "1024K"}
+var aclInheritModes = []string{"discard", "noallow", "restricted", "passthrough", "passthrough-x"}
+var checksumAlgorithms = []string{"on", "off", "fletcher2", "fletcher4", "sha256", "noparity", "sha512", "skein", "edonr", "blake3"}
+var dnodeSizes = []string{"legacy", "auto", "1k", "2k", "4k", "8k", "16k"}
+var cacheModes = []string{"all", "none", "metadata"}
+var redundantMetadataModes = []string{"all", "most", "some", "none"}
+
+// zfsDatasetProperties maps dataset attributes, not covered by the SDK, to middleware attribute names.
+// Support for these varies between TrueNAS versions, see checkDatasetPropertiesSupport.
+var zfsDatasetProperties = map[string]string{
+	"acl_inherit":              "aclinherit",
+	"checksum":                 "checksum",
+	"dnode_size":               "dnodesize",
+	"log_bias":                 "logbias",
+	"normalization":            "normalization",
+	"primary_cache":            "primarycache",
+	"redundant_metadata":       "redundant_metadata",
+	"secondary_cache":          "secondarycache",
+	"special_small_block_size": "special_small_block_size",
+	"xattr":                    "xattr",
+}
+
+// inheritValue makes inheritable property inherit its value from parent dataset,
+// upper case INHERIT used by the middleware is accepted as well
+const inheritValue = "inherit"
+
+// withInherit appends both spellings of inheritValue to list of allowed property values
+func withInherit(values []string) []string {
+	return append(append([]string{}, values...), inheritValue, strings.ToUpper(inheritValue))
+}
+
+// isInheritValue returns true if configured value is inheritValue in any case
+func isInheritValue(value string) bool {
+	return strings.EqualFold(value, inheritValue)
+}
 
 // newDatasetPath creates new datasetPath struct
 // from TrueNAS dataset ID string, that comes in format: Pool/Parent/dataset_name
 func newDatasetPath(id string) datasetPath {
 	s := strings.Split(id, "/")
 
+	if len(s) == 1 {
+		// pool root dataset
+		return datasetPath{Pool: s[0]}
+	}
+
 	if len(s) == 2 {
 		// there is no Parent
 		return datasetPath{Pool: s[0], Name: s[1], Parent: ""}
@@ -41,6 +83,10 @@ func newDatasetPath(id string) datasetPath {
 }
 
 func (d datasetPath) String() string {
+	if d.Name == "" {
+		return d.Pool
+	}
+
 	if d.Parent == "" {
 		return fmt.Sprintf("%s/%s", d.Pool, d.Name)
 	} else {
@@ -50,11 +96,12 @@ func (d datasetPath) String() string {
 
 func resourceTrueNASDataset() *schema.Resource {
 	return &schema.Resource{
-		Description:   "A TrueNAS dataset is a file system that is created within a data storage pool. Datasets can contain files, directories (child datasets), and have individual permissions or flags",
+		Description:   "A TrueNAS dataset is a file system that is created within a data storage pool. Datasets can contain files, directories (child datasets), and have individual permissions or flags. Inheritable properties accept `inherit` or `INHERIT` (`0` for `copies`) to inherit the value from parent dataset, see `property_sources` for current value sources. Plan fails when a quota change makes quotas, reservations and zvol sizes in the pool add up to more than the pool capacity. With `allow_pool_overcommit` set, a warning is reported after apply instead.",
 		CreateContext: resourceTrueNASDatasetCreate,
 		ReadContext:   resourceTrueNASDatasetRead,
 		UpdateContext: resourceTrueNASDatasetUpdate,
 		DeleteContext: resourceTrueNASDatasetDelete,
+		CustomizeDiff: resourceTrueNASDatasetCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -63,29 +110,29 @@ func resourceTrueNASDataset() *schema.Resource {
 			Update: schema.DefaultTimeout(4 * time.Minute),
 			Delete: schema.DefaultTimeout(4 * time.Minute),
 		},
-		Schema: map[string]*schema.Schema{
+		Schema: withDatasetSpaceUsage(map[string]*schema.Schema{
 			"dataset_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 			"pool": &schema.Schema{
+				Description:  "Pool name, datasets cannot be moved between pools, changing it recreates the dataset",
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validation.StringDoesNotContainAny("/"),
 				ForceNew:     true,
 			},
 			"parent": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "",
-				ForceNew: true,
+				Description: "Parent dataset path within the pool, changing it moves the dataset in place",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
 			},
 			"name": &schema.Schema{
-				Description:  "Unique identifier for the dataset. Cannot be changed after the dataset is created.",
+				Description:  "Dataset name, changing it (or `parent`) renames the dataset in place, changing `pool` recreates it",
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validation.StringDoesNotContainAny("/"),
-				ForceNew:     true,
 			},
 			"acl_mode": &schema.Schema{
 				Type:          schema.TypeString,
@@ -93,7 +140,7 @@ func resourceTrueNASDataset() *schema.Resource {
 				Optional:      true,
 				Computed:      true,
 				ConflictsWith: []string{"share_type"},
-				ValidateFunc:  validation.StringInSlice([]string{"passthrough", "restricted"}, false),
+				ValidateFunc:  validation.StringInSlice(withInherit([]string{"passthrough", "restricted"}), false),
 			},
 			"acl_type": &schema.Schema{
 				Type:     schema.TypeString,
@@ -104,7 +151,7 @@ func resourceTrueNASDataset() *schema.Resource {
 				Description:  "Choose 'on' to update the access time for files when they are read. Choose 'off' to prevent producing log traffic when reading files",
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice([]string{"on", "off"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"on", "off"}), false),
 			},
 			"case_sensitivity": &schema.Schema{
 				Type:          schema.TypeString,
@@ -123,19 +170,20 @@ func resourceTrueNASDataset() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice(supportedCompression, false),
+				ValidateFunc: validation.StringInSlice(withInherit(supportedCompression), false),
 			},
 			"copies": &schema.Schema{
+				Description:  "Number of data copies, `0` inherits the value from parent dataset",
 				Type:         schema.TypeInt,
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.IntBetween(1, 3),
+				ValidateFunc: validation.IntBetween(0, 3),
 			},
 			"deduplication": &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice([]string{"on", "off", "verify"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"on", "off", "verify"}), false),
 			},
 			"encrypted": &schema.Schema{
 				Type:     schema.TypeBool,
@@ -187,7 +235,7 @@ func resourceTrueNASDataset() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice([]string{"on", "off"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"on", "off"}), false),
 			},
 			"managed_by": &schema.Schema{
 				Type:     schema.TypeString,
@@ -235,13 +283,13 @@ func resourceTrueNASDataset() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice([]string{"on", "off"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"on", "off"}), false),
 			},
 			"record_size": &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice(recordSizes, false),
+				ValidateFunc: validation.StringInSlice(withInherit(recordSizes), false),
 			},
 			"share_type": &schema.Schema{
 				Type:         schema.TypeString,
@@ -255,15 +303,125 @@ func resourceTrueNASDataset() *schema.Resource {
 				Description:  "Sets the data write synchronization. `inherit` takes the sync settings from the parent dataset, `standard` uses the settings that have been requested by the client software, `always` waits for data writes to complete, and `disabled` never waits for writes to complete.",
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice([]string{"always", "standard", "disabled", "inherit"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"always", "standard", "disabled"}), false),
 			},
 			"snap_dir": &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice([]string{"visible", "hidden"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"visible", "hidden"}), false),
 			},
-		},
+			"acl_inherit": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Determine how ACL entries are inherited when files and directories are created. See the zfs(8) aclinherit property.",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit(aclInheritModes), false),
+			},
+			"checksum": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Checksum algorithm used to verify data integrity",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit(checksumAlgorithms), false),
+			},
+			"dnode_size": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Dnode size, `auto` is recommended when `xattr` is `sa`",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit(dnodeSizes), false),
+			},
+			"log_bias": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "`latency` uses log devices for synchronous writes, `throughput` writes directly to the pool",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"latency", "throughput"}), false),
+			},
+			"normalization": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Unicode normalization applied to file names, can only be set when dataset is created",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"none", "formc", "formd", "formkc", "formkd"}, false),
+			},
+			"primary_cache": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "What is cached in ARC",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit(cacheModes), false),
+			},
+			"redundant_metadata": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Which metadata is stored redundantly",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit(redundantMetadataModes), false),
+			},
+			"secondary_cache": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "What is cached in L2ARC",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit(cacheModes), false),
+			},
+			"special_small_block_size": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "Blocks up to this size are stored on special allocation class vdevs, `0` disables small blocks on special vdevs",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit(append([]string{"0"}, recordSizes...)), false),
+			},
+			"xattr": &schema.Schema{
+				Type:         schema.TypeString,
+				Description:  "`sa` stores extended attributes as system attributes, `on` uses hidden directories",
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"on", "sa"}), false),
+			},
+			"all_user_properties": &schema.Schema{
+				Description: "Read back all locally set user properties, so that user properties not in `user_properties` are removed",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"delete_recursive": &schema.Schema{
+				Description: "Destroy child datasets and snapshots together with the dataset",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"delete_force": &schema.Schema{
+				Description: "Destroy the dataset even if it is busy",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"allow_pool_overcommit": &schema.Schema{
+				Description: "Allow `quota_bytes` or `ref_quota_bytes` changes that make quotas, reservations and zvol sizes in the pool add up to more than the pool capacity, these fail at plan time otherwise",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"deletion_protection": &schema.Schema{
+				Description: "Refuse to destroy the dataset if it uses more than `deletion_protection_max_used_bytes` or has child datasets or zvols not created by Terraform. Datasets and zvols created by the provider are marked with `terraform-provider-truenas:managed` user property, imported ones are treated as unmanaged",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"deletion_protection_max_used_bytes": &schema.Schema{
+				Description:  "Largest used space in bytes, including children and snapshots, a protected dataset can be destroyed with",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1048576,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"user_properties":  resourceUserPropertiesSchema(),
+			"property_sources": resourcePropertySourcesSchema(),
+		}),
 	}
 }
 
@@ -288,7 +446,13 @@ func resourceTrueNASDatasetCreate(ctx context.Context, d *schema.ResourceData, m
 
 	log.Printf("[INFO] TrueNAS dataset (%s) created", resp.Id)
 
-	return resourceTrueNASDatasetRead(ctx, d, m)
+	diags := resourceTrueNASDatasetRead(ctx, d, m)
+
+	if !diags.HasError() && datasetPlannedCommitment(d) > 0 {
+		diags = append(diags, poolCapacityWarning(ctx, c, d.Get("pool").(string))...)
+	}
+
+	return diags
 }
 
 func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -320,7 +484,7 @@ func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	if resp.Aclmode != nil && resp.Aclmode.Value != nil {
-		d.Set("acl_mode", strings.ToLower(*resp.Aclmode.Value))
+		d.Set("acl_mode", flattenInheritableProperty(d, "acl_mode", resp.Aclmode))
 	}
 
 	if resp.Acltype != nil && resp.Acltype.Value != nil {
@@ -328,7 +492,7 @@ func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	if resp.Atime != nil && resp.Atime.Value != nil {
-		d.Set("atime", strings.ToLower(*resp.Atime.Value))
+		d.Set("atime", flattenInheritableProperty(d, "atime", resp.Atime))
 	}
 
 	if resp.Casesensitivity != nil && resp.Casesensitivity.Value != nil {
@@ -341,15 +505,15 @@ func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	if resp.Compression != nil && resp.Compression.Value != nil {
-		d.Set("compression", strings.ToLower(*resp.Compression.Value))
+		d.Set("compression", flattenInheritableProperty(d, "compression", resp.Compression))
 	}
 
 	if resp.Deduplication != nil && resp.Deduplication.Value != nil {
-		d.Set("deduplication", strings.ToLower(*resp.Deduplication.Value))
+		d.Set("deduplication", flattenInheritableProperty(d, "deduplication", resp.Deduplication))
 	}
 
 	if resp.Exec != nil && resp.Exec.Value != nil {
-		d.Set("exec", strings.ToLower(*resp.Exec.Value))
+		d.Set("exec", flattenInheritableProperty(d, "exec", resp.Exec))
 	}
 
 	if resp.Managedby != nil && resp.Managedby.Value != nil {
@@ -363,6 +527,11 @@ func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m i
 			return diag.Errorf("error parsing copies: %s", err)
 		}
 
+		// 0 means inherit
+		if isInheritedProperty(resp.Copies) && d.Get("copies").(int) == 0 {
+			copies = 0
+		}
+
 		d.Set("copies", copies)
 	}
 
@@ -427,11 +596,16 @@ func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m i
 	}
 
 	if resp.Readonly != nil && resp.Readonly.Value != nil {
-		d.Set("readonly", strings.ToLower(*resp.Readonly.Value))
+		d.Set("readonly", flattenInheritableProperty(d, "readonly", resp.Readonly))
 	}
 
 	if resp.Recordsize != nil && resp.Recordsize.Value != nil {
-		d.Set("record_size", *resp.Recordsize.Value)
+		// record size is reported in upper case, eg. 128K
+		if isInheritedProperty(resp.Recordsize) && isInheritValue(d.Get("record_size").(string)) {
+			d.Set("record_size", d.Get("record_size").(string))
+		} else {
+			d.Set("record_size", *resp.Recordsize.Value)
+		}
 	}
 
 	// TODO: doublecheck, does not seem to be ever returned
@@ -442,11 +616,11 @@ func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m i
 	//}
 
 	if resp.Sync != nil {
-		d.Set("sync", strings.ToLower(*resp.Sync.Value))
+		d.Set("sync", flattenInheritableProperty(d, "sync", resp.Sync))
 	}
 
 	if resp.Snapdir != nil && resp.Snapdir.Value != nil {
-		d.Set("snap_dir", strings.ToLower(*resp.Snapdir.Value))
+		d.Set("snap_dir", flattenInheritableProperty(d, "snap_dir", resp.Snapdir))
 	}
 
 	if resp.EncryptionAlgorithm != nil && resp.EncryptionAlgorithm.Value != nil {
@@ -467,12 +641,56 @@ func resourceTrueNASDatasetRead(ctx context.Context, d *schema.ResourceData, m i
 		d.Set("encrypted", *resp.Encrypted)
 	}
 
+	if err := setUserProperties(resp, d); err != nil {
+		return diag.Errorf("error setting user_properties: %s", err)
+	}
+
+	zfsProps := map[string]*api.CompositeValue{}
+
+	for k, attr := range zfsDatasetProperties {
+		if v := getZFSDatasetProperty(resp, attr); v != nil {
+			zfsProps[k] = v
+			d.Set(k, flattenZFSDatasetProperty(d, k, v))
+		}
+	}
+
+	sources := flattenPropertySources(map[string]*api.CompositeValue{
+		"acl_mode":      resp.Aclmode,
+		"atime":         resp.Atime,
+		"compression":   resp.Compression,
+		"copies":        resp.Copies,
+		"deduplication": resp.Deduplication,
+		"exec":          resp.Exec,
+		"readonly":      resp.Readonly,
+		"record_size":   resp.Recordsize,
+		"snap_dir":      resp.Snapdir,
+		"sync":          resp.Sync,
+	})
+
+	for k, v := range flattenPropertySources(zfsProps) {
+		sources[k] = v
+	}
+
+	if err := d.Set("property_sources", sources); err != nil {
+		return diag.Errorf("error setting property_sources: %s", err)
+	}
+
+	if err := setDatasetSpaceUsage(resp, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return diags
 }
 
 func resourceTrueNASDatasetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*api.APIClient)
 
+	if d.HasChanges("parent", "name") {
+		if err := renameDataset(ctx, c, d); err != nil {
+			return diag.Errorf("error renaming dataset: %s", err)
+		}
+	}
+
 	input := expandDatasetForUpdate(d)
 
 	log.Printf("[DEBUG] Updating TrueNAS dataset: %+v", input)
@@ -489,7 +707,13 @@ func resourceTrueNASDatasetUpdate(ctx context.Context, d *schema.ResourceData, m
 
 	log.Printf("[INFO] TrueNAS dataset (%s) updated", d.Id())
 
-	return resourceTrueNASDatasetRead(ctx, d, m)
+	diags := resourceTrueNASDatasetRead(ctx, d, m)
+
+	if !diags.HasError() && d.HasChanges("quota_bytes", "ref_quota_bytes") && datasetPlannedCommitment(d) > 0 {
+		diags = append(diags, poolCapacityWarning(ctx, c, d.Get("pool").(string))...)
+	}
+
+	return diags
 }
 
 func resourceTrueNASDatasetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -498,16 +722,40 @@ func resourceTrueNASDatasetDelete(ctx context.Context, d *schema.ResourceData, m
 	c := m.(*api.APIClient)
 	id := d.Id()
 
+	if d.Get("deletion_protection").(bool) {
+		resp, _, err := c.DatasetApi.GetDataset(ctx, id).Execute()
+
+		if err != nil {
+			var body []byte
+			if apiErr, ok := err.(*api.GenericOpenAPIError); ok {
+				body = apiErr.Body()
+			}
+			return diag.Errorf("error getting dataset: %s\n%s", err, body)
+		}
+
+		used := 0
+
+		if resp.Used != nil {
+			used, _ = strconv.Atoi(resp.Used.Rawvalue)
+		}
+
+		if err := checkDatasetDeletionProtection(id, used, flattenDatasetChildren(resp), d.Get("deletion_protection_max_used_bytes").(int)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	log.Printf("[DEBUG] Deleting TrueNAS dataset: %s", id)
 
-	_, err := c.DatasetApi.DeleteDataset(ctx, id).Execute()
+	// SDK does not support delete options
+	input := map[string]interface{}{
+		"recursive": d.Get("delete_recursive").(bool),
+		"force":     d.Get("delete_force").(bool),
+	}
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/pool/dataset/id/%s", url.PathEscape(id)), input, nil)
 
 	if err != nil {
-		var body []byte
-		if apiErr, ok := err.(*api.GenericOpenAPIError); ok {
-			body = apiErr.Body()
-		}
-		return diag.Errorf("error deleting dataset: %s\n%s", err, body)
+		return diag.Errorf("error deleting dataset: %s", err)
 	}
 
 	log.Printf("[INFO] TrueNAS dataset (%s) deleted", id)
@@ -516,6 +764,77 @@ func resourceTrueNASDatasetDelete(ctx context.Context, d *schema.ResourceData, m
 	return diags
 }
 
+// renameDataset renames (and re-parents) dataset or zvol within the pool and updates the ID
+func renameDataset(ctx context.Context, c *api.APIClient, d *schema.ResourceData) error {
+	p := datasetPath{
+		Pool:   d.Get("pool").(string),
+		Parent: d.Get("parent").(string),
+		Name:   d.Get("name").(string),
+	}
+
+	newName := p.String()
+
+	log.Printf("[DEBUG] Renaming TrueNAS dataset %s to %s", d.Id(), newName)
+
+	input := map[string]interface{}{
+		"new_name": newName,
+	}
+
+	_, err := callAPI(ctx, c, http.MethodPost, fmt.Sprintf("/pool/dataset/id/%s/rename", url.PathEscape(d.Id())), input, nil)
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] TrueNAS dataset (%s) renamed to %s", d.Id(), newName)
+
+	d.SetId(newName)
+
+	return nil
+}
+
+// resourceTrueNASDatasetCustomizeDiff plans copies = 0 (inherit), SDK ignores zero
+// values of computed attributes set in configuration. Also plans new ID on rename,
+// rejects quotas overcommitting the pool and properties the target TrueNAS version does not support.
+func resourceTrueNASDatasetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// rename changes the ID and mount point, dependents must not plan with the old path
+	if d.Id() != "" && d.HasChanges("parent", "name") {
+		for _, k := range []string{"dataset_id", "mount_point"} {
+			if err := d.SetNewComputed(k); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !d.Get("allow_pool_overcommit").(bool) {
+		if planned, grows := plannedCommitment(d, "quota_bytes", "ref_quota_bytes"); grows {
+			if err := validatePoolCapacity(ctx, m.(*api.APIClient), d, planned); err != nil {
+				return err
+			}
+		}
+	}
+
+	config := d.GetRawConfig()
+
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	if err := checkDatasetPropertiesSupport(ctx, m.(*api.APIClient), d); err != nil {
+		return err
+	}
+
+	copies := config.GetAttr("copies")
+
+	if copies.IsKnown() && !copies.IsNull() && copies.AsBigFloat().Sign() == 0 {
+		if d.Get("copies").(int) != 0 {
+			return d.SetNew("copies", 0)
+		}
+	}
+
+	return nil
+}
+
 func expandDataset(d *schema.ResourceData) api.CreateDatasetParams {
 	p := datasetPath{
 		Pool:   d.Get("pool").(string),
@@ -635,6 +954,14 @@ func expandDataset(d *schema.ResourceData) api.CreateDatasetParams {
 
 	input.EncryptionOptions = encOptions
 
+	setAdditionalProperty(&input.AdditionalProperties, "user_properties", expandManagedUserProperties(d.Get("user_properties").(map[string]interface{})))
+
+	for k, attr := range zfsDatasetProperties {
+		if v, ok := d.GetOk(k); ok {
+			setAdditionalProperty(&input.AdditionalProperties, attr, expandZFSDatasetProperty(k, v.(string)))
+		}
+	}
+
 	input.Type = getStringPtr(datasetType)
 	return input
 }
@@ -660,6 +987,9 @@ func expandDatasetForUpdate(d *schema.ResourceData) api.UpdateDatasetParams {
 
 	if copies, ok := d.GetOk("copies"); ok {
 		input.Copies = getInt32Ptr(int32(copies.(int)))
+	} else if d.HasChange("copies") {
+		// copies is an integer in the SDK, INHERIT has to be sent as an additional property
+		setAdditionalProperty(&input.AdditionalProperties, "copies", "INHERIT")
 	}
 
 	if exec, ok := d.GetOk("exec"); ok {
@@ -694,5 +1024,736 @@ func expandDatasetForUpdate(d *schema.ResourceData) api.UpdateDatasetParams {
 		input.Snapdir = getStringPtr(strings.ToUpper(snapDir.(string)))
 	}
 
+	if d.HasChange("user_properties") {
+		o, n := d.GetChange("user_properties")
+
+		setAdditionalProperty(&input.AdditionalProperties, "user_properties_update", expandUserPropertiesUpdate(o.(map[string]interface{}), n.(map[string]interface{})))
+	}
+
+	// only changed values are sent, older TrueNAS versions reject unknown attributes
+	for k, attr := range zfsDatasetProperties {
+		if v, ok := d.GetOk(k); ok && d.HasChange(k) {
+			setAdditionalProperty(&input.AdditionalProperties, attr, expandZFSDatasetProperty(k, v.(string)))
+		}
+	}
+
 	return input
 }
+
+// user properties in these namespaces back regular dataset attributes (comments, quota alerts, managed_by)
+// or mark datasets created by the provider
+var reservedUserPropertyNamespaces = []string{"org.freenas:", "org.truenas:", "terraform-provider-truenas:"}
+
+// managedUserProperty marks datasets and zvols created by the provider
+const managedUserProperty = "terraform-provider-truenas:managed"
+
+var userPropertyKeyRegexp = regexp.MustCompile(`^[a-z0-9_.+-]+:[a-zA-Z0-9_.:+-]+$`)
+
+func isReservedUserProperty(key string) bool {
+	for _, ns := range reservedUserPropertyNamespaces {
+		if strings.HasPrefix(key, ns) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateUserProperties(i interface{}, k string) ([]string, []error) {
+	var errs []error
+
+	for key, value := range i.(map[string]interface{}) {
+		if !userPropertyKeyRegexp.MatchString(key) {
+			errs = append(errs, fmt.Errorf("%s: %q must be in module:property format, eg. com.example:owner", k, key))
+		}
+
+		if isReservedUserProperty(key) {
+			errs = append(errs, fmt.Errorf("%s: %q is reserved by TrueNAS or the provider", k, key))
+		}
+
+		if len(value.(string)) > 8191 {
+			errs = append(errs, fmt.Errorf("%s: value of %q must be at most 8191 characters", k, key))
+		}
+	}
+
+	return nil, errs
+}
+
+func resourceUserPropertiesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description:  "ZFS user properties in `module:property` format, eg. `com.example:owner`. Only these keys are read back, unless `all_user_properties` is set.",
+		Type:         schema.TypeMap,
+		Optional:     true,
+		ValidateFunc: validateUserProperties,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// flattenUserProperties returns locally set user properties, TrueNAS SCALE returns them
+// in user_properties attribute, CORE as top level dataset attributes
+func flattenUserProperties(resp *api.Dataset) map[string]string {
+	result := map[string]string{}
+
+	props := map[string]interface{}{}
+
+	if userProps, ok := resp.AdditionalProperties["user_properties"].(map[string]interface{}); ok {
+		props = userProps
+	} else {
+		for k, v := range resp.AdditionalProperties {
+			if strings.Contains(k, ":") {
+				props[k] = v
+			}
+		}
+	}
+
+	for k, v := range props {
+		prop, ok := v.(map[string]interface{})
+
+		if !ok || isReservedUserProperty(k) {
+			continue
+		}
+
+		// inherited properties belong to the parent
+		if source, ok := prop["source"].(string); ok && source != "LOCAL" {
+			continue
+		}
+
+		if value, ok := prop["value"].(string); ok {
+			result[k] = value
+		} else if value, ok := prop["rawvalue"].(string); ok {
+			result[k] = value
+		}
+	}
+
+	return result
+}
+
+// setUserProperties sets managed user properties, or all of them if all_user_properties is set
+func setUserProperties(resp *api.Dataset, d *schema.ResourceData) error {
+	props := flattenUserProperties(resp)
+
+	if !d.Get("all_user_properties").(bool) {
+		managed := d.Get("user_properties").(map[string]interface{})
+
+		for k := range props {
+			if _, ok := managed[k]; !ok {
+				delete(props, k)
+			}
+		}
+	}
+
+	return d.Set("user_properties", props)
+}
+
+func expandUserProperties(props map[string]interface{}) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(props))
+
+	for k, v := range props {
+		result = append(result, map[string]interface{}{
+			"key":   k,
+			"value": v.(string),
+		})
+	}
+
+	return result
+}
+
+// expandManagedUserProperties returns user properties for a new dataset or zvol, including the managed marker
+func expandManagedUserProperties(props map[string]interface{}) []map[string]interface{} {
+	return append(expandUserProperties(props), map[string]interface{}{
+		"key":   managedUserProperty,
+		"value": "true",
+	})
+}
+
+// expandUserPropertiesUpdate returns user_properties_update entries that turn o into n
+func expandUserPropertiesUpdate(o map[string]interface{}, n map[string]interface{}) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+
+	for k, v := range n {
+		if old, ok := o[k]; !ok || old != v {
+			result = append(result, map[string]interface{}{
+				"key":   k,
+				"value": v.(string),
+			})
+		}
+	}
+
+	for k := range o {
+		if _, ok := n[k]; !ok {
+			result = append(result, map[string]interface{}{
+				"key":    k,
+				"remove": true,
+			})
+		}
+	}
+
+	return result
+}
+
+// setAdditionalProperty sets request attribute not covered by the SDK
+func setAdditionalProperty(props *map[string]interface{}, key string, value interface{}) {
+	if *props == nil {
+		*props = map[string]interface{}{}
+	}
+
+	(*props)[key] = value
+}
+
+func isInheritedProperty(v *api.CompositeValue) bool {
+	return v.Source != nil && *v.Source != "LOCAL"
+}
+
+// flattenInheritableProperty returns configured inheritValue if property is configured to inherit
+// and it is not set locally, otherwise lower case property value
+func flattenInheritableProperty(d *schema.ResourceData, key string, v *api.CompositeValue) string {
+	if configured := d.Get(key).(string); isInheritedProperty(v) && isInheritValue(configured) {
+		return configured
+	}
+
+	return strings.ToLower(*v.Value)
+}
+
+func resourcePropertySourcesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Source of inheritable property values, eg. `LOCAL`, `INHERITED` or `DEFAULT`",
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+func flattenPropertySources(props map[string]*api.CompositeValue) map[string]string {
+	result := map[string]string{}
+
+	for k, v := range props {
+		if v != nil && v.Source != nil {
+			result[k] = *v.Source
+		}
+	}
+
+	return result
+}
+
+// getCompositeValueBytes parses raw value of a dataset property not covered by the SDK
+func getCompositeValueBytes(props map[string]interface{}, key string) (int, bool) {
+	prop, ok := props[key].(map[string]interface{})
+
+	if !ok {
+		return 0, false
+	}
+
+	raw, ok := prop["rawvalue"].(string)
+
+	if !ok {
+		return 0, false
+	}
+
+	v, err := strconv.Atoi(raw)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// flattenDatasetChildren returns IDs of direct child datasets
+// flattenDatasetChildren returns descendants not created by the provider. Children of managed
+// datasets are checked too, they may hold data the managed child's resource doesn't know about
+func flattenDatasetChildren(resp *api.Dataset) []string {
+	return unmanagedDatasetChildren(resp.AdditionalProperties)
+}
+
+func unmanagedDatasetChildren(ds map[string]interface{}) []string {
+	result := []string{}
+
+	children, ok := ds["children"].([]interface{})
+
+	if !ok {
+		return result
+	}
+
+	for _, child := range children {
+		c, ok := child.(map[string]interface{})
+
+		if !ok {
+			continue
+		}
+
+		if id, ok := c["id"].(string); ok && !isManagedDataset(c) {
+			result = append(result, id)
+		}
+
+		result = append(result, unmanagedDatasetChildren(c)...)
+	}
+
+	return result
+}
+
+// isManagedDataset reports whether the dataset carries managedUserProperty, SCALE returns it
+// in user_properties attribute, CORE as top level dataset attribute
+func isManagedDataset(ds map[string]interface{}) bool {
+	prop, ok := ds[managedUserProperty].(map[string]interface{})
+
+	if userProps, found := ds["user_properties"].(map[string]interface{}); found {
+		prop, ok = userProps[managedUserProperty].(map[string]interface{})
+	}
+
+	if !ok {
+		return false
+	}
+
+	// inherited marker belongs to the parent
+	if source, ok := prop["source"].(string); ok && source != "LOCAL" {
+		return false
+	}
+
+	value, _ := prop["value"].(string)
+
+	return value == "true"
+}
+
+func checkDatasetDeletionProtection(id string, used int, children []string, maxUsed int) error {
+	if len(children) > 0 {
+		return fmt.Errorf("dataset %s is protected by deletion_protection and has child datasets not created by Terraform: %s", id, strings.Join(children, ", "))
+	}
+
+	if used > maxUsed {
+		return fmt.Errorf("dataset %s is protected by deletion_protection and uses %d bytes (limit %d), disable deletion_protection to destroy it", id, used, maxUsed)
+	}
+
+	return nil
+}
+
+// withDatasetSpaceUsage adds computed space accounting attributes to dataset or zvol schema
+func withDatasetSpaceUsage(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["used_bytes"] = &schema.Schema{
+		Description: "Space used by the dataset and all its descendants in bytes",
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+
+	s["available_bytes"] = &schema.Schema{
+		Description: "Space available to the dataset and all its children in bytes",
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+
+	s["used_by_snapshots_bytes"] = &schema.Schema{
+		Description: "Space used by snapshots of the dataset in bytes",
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+
+	s["used_by_children_bytes"] = &schema.Schema{
+		Description: "Space used by children of the dataset in bytes",
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+
+	s["referenced_bytes"] = &schema.Schema{
+		Description: "Space referenced by the dataset, shared with other datasets or snapshots, in bytes",
+		Type:        schema.TypeInt,
+		Computed:    true,
+	}
+
+	s["compress_ratio"] = &schema.Schema{
+		Description: "Compression ratio achieved for the space referenced by the dataset",
+		Type:        schema.TypeFloat,
+		Computed:    true,
+	}
+
+	return s
+}
+
+// setDatasetSpaceUsage sets attributes added by withDatasetSpaceUsage
+func setDatasetSpaceUsage(resp *api.Dataset, d *schema.ResourceData) error {
+	if resp.Used != nil {
+		used, err := strconv.Atoi(resp.Used.Rawvalue)
+
+		if err != nil {
+			return fmt.Errorf("error parsing used: %s", err)
+		}
+
+		d.Set("used_bytes", used)
+	}
+
+	if resp.Available != nil {
+		available, err := strconv.Atoi(resp.Available.Rawvalue)
+
+		if err != nil {
+			return fmt.Errorf("error parsing available: %s", err)
+		}
+
+		d.Set("available_bytes", available)
+	}
+
+	if v, ok := getCompositeValueBytes(resp.AdditionalProperties, "usedbysnapshots"); ok {
+		d.Set("used_by_snapshots_bytes", v)
+	}
+
+	if v, ok := getCompositeValueBytes(resp.AdditionalProperties, "usedbychildren"); ok {
+		d.Set("used_by_children_bytes", v)
+	}
+
+	if v, ok := getCompositeValueBytes(resp.AdditionalProperties, "referenced"); ok {
+		d.Set("referenced_bytes", v)
+	}
+
+	if prop, ok := resp.AdditionalProperties["compressratio"].(map[string]interface{}); ok {
+		// raw value has no suffix, eg. 1.52
+		if raw, ok := prop["rawvalue"].(string); ok {
+			ratio, err := strconv.ParseFloat(raw, 64)
+
+			if err != nil {
+				return fmt.Errorf("error parsing compressratio: %s", err)
+			}
+
+			d.Set("compress_ratio", ratio)
+		}
+	}
+
+	return nil
+}
+
+// datasetCommitment returns space promised to the dataset by its quota, reservation or volume size
+func datasetCommitment(ds *api.Dataset) int {
+	result := 0
+
+	for _, v := range []*api.CompositeValue{ds.Quota, ds.Refquota, ds.Reservation, ds.Refreservation, ds.Volsize} {
+		if v == nil {
+			continue
+		}
+
+		if size, err := strconv.Atoi(v.Rawvalue); err == nil && size > result {
+			result = size
+		}
+	}
+
+	return result
+}
+
+// sumDatasetCommitments sums space promised to datasets in the pool, planned values override current ones.
+// Commitments nested under a dataset that already has one are not counted twice.
+func sumDatasetCommitments(datasets []api.Dataset, pool string, planned map[string]int) int {
+	commitments := map[string]int{}
+
+	for i := range datasets {
+		if strings.HasPrefix(datasets[i].Id, pool+"/") {
+			commitments[datasets[i].Id] = datasetCommitment(&datasets[i])
+		}
+	}
+
+	for id, v := range planned {
+		commitments[id] = v
+	}
+
+	ids := make([]string, 0, len(commitments))
+
+	for id := range commitments {
+		ids = append(ids, id)
+	}
+
+	// parents sort before their children
+	sort.Strings(ids)
+
+	total := 0
+	counted := []string{}
+
+	for _, id := range ids {
+		if commitments[id] == 0 {
+			continue
+		}
+
+		nested := false
+
+		for _, parent := range counted {
+			if strings.HasPrefix(id, parent+"/") {
+				nested = true
+				break
+			}
+		}
+
+		if !nested {
+			total += commitments[id]
+			counted = append(counted, id)
+		}
+	}
+
+	return total
+}
+
+// checkPoolCapacity returns a warning message if quotas, reservations and volume sizes in the pool
+// add up to more than the pool can hold
+func checkPoolCapacity(ctx context.Context, c *api.APIClient, pool string, planned map[string]int) (string, error) {
+	list, _, err := c.DatasetApi.ListDatasets(ctx).Execute()
+
+	if err != nil {
+		return "", fmt.Errorf("error listing datasets: %s", err)
+	}
+
+	// children may only be returned nested under their parents
+	datasets := flattenDatasetTree(list)
+	capacity := 0
+
+	for _, ds := range datasets {
+		if ds.Id == pool && ds.Used != nil && ds.Available != nil {
+			used, _ := strconv.Atoi(ds.Used.Rawvalue)
+			available, _ := strconv.Atoi(ds.Available.Rawvalue)
+			capacity = used + available
+		}
+	}
+
+	if capacity == 0 {
+		return "", fmt.Errorf("pool %s not found", pool)
+	}
+
+	committed := sumDatasetCommitments(datasets, pool, planned)
+
+	if committed > capacity {
+		return fmt.Sprintf("quotas, reservations and volume sizes in pool %s add up to %d bytes, more than the %d bytes the pool can hold", pool, committed, capacity), nil
+	}
+
+	return "", nil
+}
+
+// poolCapacityWarning returns a warning if the pool is overcommitted after apply
+func poolCapacityWarning(ctx context.Context, c *api.APIClient, pool string) diag.Diagnostics {
+	msg, err := checkPoolCapacity(ctx, c, pool, nil)
+
+	if err != nil {
+		log.Printf("[DEBUG] Skipping pool capacity check: %s", err)
+		return nil
+	}
+
+	if msg == "" {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Pool %s is overcommitted", pool),
+			Detail:   fmt.Sprintf("%s, writes may fail with out of space errors before quotas are reached.", msg),
+		},
+	}
+}
+
+// validatePoolCapacity fails the plan if the planned commitment of a dataset or zvol overcommits the pool
+func validatePoolCapacity(ctx context.Context, c *api.APIClient, d *schema.ResourceDiff, planned int) error {
+	id := plannedDatasetID(d)
+
+	if id == "" {
+		return nil
+	}
+
+	commitments := map[string]int{id: planned}
+
+	// renamed or replaced dataset gives up its current commitment
+	if d.Id() != "" && d.Id() != id {
+		commitments[d.Id()] = 0
+	}
+
+	msg, err := checkPoolCapacity(ctx, c, d.Get("pool").(string), commitments)
+
+	if err != nil {
+		log.Printf("[DEBUG] Skipping pool capacity check: %s", err)
+		return nil
+	}
+
+	if msg != "" {
+		return fmt.Errorf("%s, set allow_pool_overcommit to plan it anyway", msg)
+	}
+
+	return nil
+}
+
+// plannedCommitment returns largest of planned values of keys and whether it grows
+// over the current one, only growing commitments are checked against pool capacity.
+// Values not known until apply, including unset computed ones of new resources, are not counted.
+func plannedCommitment(d *schema.ResourceDiff, keys ...string) (int, bool) {
+	current, planned := 0, 0
+
+	for _, k := range keys {
+		if !d.NewValueKnown(k) {
+			continue
+		}
+
+		o, n := d.GetChange(k)
+
+		if o.(int) > current {
+			current = o.(int)
+		}
+
+		if n.(int) > planned {
+			planned = n.(int)
+		}
+	}
+
+	return planned, planned > current
+}
+
+// plannedDatasetID returns ID the dataset will have after apply or empty string if it is not known yet
+func plannedDatasetID(d *schema.ResourceDiff) string {
+	for _, k := range []string{"pool", "parent", "name"} {
+		if !d.NewValueKnown(k) {
+			return ""
+		}
+	}
+
+	p := datasetPath{
+		Pool:   d.Get("pool").(string),
+		Parent: d.Get("parent").(string),
+		Name:   d.Get("name").(string),
+	}
+
+	return p.String()
+}
+
+// datasetPlannedCommitment returns largest of configured dataset quotas
+func datasetPlannedCommitment(d *schema.ResourceData) int {
+	quota := d.Get("quota_bytes").(int)
+
+	if refQuota := d.Get("ref_quota_bytes").(int); refQuota > quota {
+		return refQuota
+	}
+
+	return quota
+}
+
+// getCompositeValue returns dataset property not covered by the SDK
+func getCompositeValue(props map[string]interface{}, key string) *api.CompositeValue {
+	prop, ok := props[key].(map[string]interface{})
+
+	if !ok {
+		return nil
+	}
+
+	result := &api.CompositeValue{}
+
+	if v, ok := prop["value"].(string); ok {
+		result.Value = &v
+	}
+
+	if v, ok := prop["rawvalue"].(string); ok {
+		result.Rawvalue = v
+	}
+
+	if v, ok := prop["source"].(string); ok {
+		result.Source = &v
+	}
+
+	if result.Value == nil {
+		return nil
+	}
+
+	return result
+}
+
+// getZFSDatasetProperty returns one of zfsDatasetProperties from dataset response
+func getZFSDatasetProperty(resp *api.Dataset, attr string) *api.CompositeValue {
+	// xattr is the only one parsed by the SDK
+	if attr == "xattr" {
+		if resp.Xattr == nil || resp.Xattr.Value == nil {
+			return nil
+		}
+
+		return resp.Xattr
+	}
+
+	return getCompositeValue(resp.AdditionalProperties, attr)
+}
+
+// expandZFSDatasetProperty converts attribute value to the format middleware expects
+func expandZFSDatasetProperty(key string, value string) interface{} {
+	if isInheritValue(value) {
+		return "INHERIT"
+	}
+
+	if key == "special_small_block_size" {
+		return parseBlockSize(value)
+	}
+
+	return strings.ToUpper(value)
+}
+
+func flattenZFSDatasetProperty(d *schema.ResourceData, key string, v *api.CompositeValue) string {
+	if key != "special_small_block_size" || (isInheritedProperty(v) && isInheritValue(d.Get(key).(string))) {
+		return flattenInheritableProperty(d, key, v)
+	}
+
+	// raw value is in bytes, eg. 65536
+	size, err := strconv.Atoi(v.Rawvalue)
+
+	if err != nil {
+		return strings.ToUpper(*v.Value)
+	}
+
+	return formatBlockSize(size)
+}
+
+// formatBlockSize formats size in bytes the way recordSizes are listed, eg. 64K
+func formatBlockSize(size int) string {
+	if size >= 1024 && size%1024 == 0 {
+		return fmt.Sprintf("%dK", size/1024)
+	}
+
+	return strconv.Itoa(size)
+}
+
+// checkDatasetPropertiesSupport makes sure configured properties are accepted by the target TrueNAS,
+// properties missing in the middleware of older versions are otherwise silently ignored or rejected on apply
+func checkDatasetPropertiesSupport(ctx context.Context, c *api.APIClient, d *schema.ResourceDiff) error {
+	config := d.GetRawConfig()
+	unsupported := []string{}
+
+	var create, update map[string]bool
+
+	for k, attr := range zfsDatasetProperties {
+		if v := config.GetAttr(k); v.IsNull() || (d.Id() != "" && !d.HasChange(k)) {
+			continue
+		}
+
+		method := "update"
+		attrs := update
+
+		if d.Id() == "" || k == "normalization" {
+			method = "create"
+			attrs = create
+		}
+
+		if attrs == nil {
+			var err error
+
+			attrs, err = getMethodAttributes(ctx, c, "pool.dataset", method)
+
+			if err != nil {
+				// can not tell, let middleware validate the request
+				log.Printf("[DEBUG] Skipping dataset property support check: %s", err)
+				return nil
+			}
+
+			if method == "create" {
+				create = attrs
+			} else {
+				update = attrs
+			}
+		}
+
+		if !attrs[attr] {
+			unsupported = append(unsupported, k)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("%s not supported by this TrueNAS version, remove from configuration", strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
@@ -6,8 +6,12 @@ import (
 	api "github.com/dariusbakunas/truenas-go-sdk"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -43,6 +47,11 @@ func TestAccResourceTruenasDataset_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "readonly", "off"),
 					resource.TestCheckResourceAttr(resourceName, "record_size", "256K"),
 					resource.TestCheckResourceAttr(resourceName, "case_sensitivity", "mixed"),
+					resource.TestCheckResourceAttr(resourceName, "user_properties.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "user_properties.com.example:owner", "terraform"),
+					resource.TestCheckResourceAttrSet(resourceName, "used_bytes"),
+					resource.TestCheckResourceAttrSet(resourceName, "available_bytes"),
+					resource.TestCheckResourceAttrSet(resourceName, "compress_ratio"),
 					testAccCheckTruenasDatasetResourceExists(resourceName, &dataset),
 				),
 			},
@@ -50,6 +59,99 @@ func TestAccResourceTruenasDataset_basic(t *testing.T) {
 	})
 }
 
+func TestAccResourceTruenasDataset_inherit(t *testing.T) {
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	name := fmt.Sprintf("%s-%s", testResourcePrefix, suffix)
+	resourceName := "truenas_dataset.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceTruenasDatasetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasDatasetInheritConfig(testPoolName, name, "zstd", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "compression", "zstd"),
+					resource.TestCheckResourceAttr(resourceName, "copies", "2"),
+					resource.TestCheckResourceAttr(resourceName, "property_sources.compression", "LOCAL"),
+					resource.TestCheckResourceAttr(resourceName, "property_sources.copies", "LOCAL"),
+				),
+			},
+			{
+				Config: testAccCheckResourceTruenasDatasetInheritConfig(testPoolName, name, "inherit", 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "compression", "inherit"),
+					resource.TestCheckResourceAttr(resourceName, "copies", "0"),
+					resource.TestCheckResourceAttr(resourceName, "property_sources.compression", "INHERITED"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckResourceTruenasDatasetInheritConfig(pool string, name string, compression string, copies int) string {
+	return fmt.Sprintf(`
+	resource "truenas_dataset" "test" {
+		name = "%s"
+		pool = "%s"
+		compression = "%s"
+		copies = %d
+	}
+	`, name, pool, compression, copies)
+}
+
+func TestAccResourceTruenasDataset_rename(t *testing.T) {
+	var dataset api.Dataset
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	parent := fmt.Sprintf("%s-%s-parent", testResourcePrefix, suffix)
+	name := fmt.Sprintf("%s-%s", testResourcePrefix, suffix)
+	resourceName := "truenas_dataset.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceTruenasDatasetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasDatasetRenameConfig(testPoolName, parent, name, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "dataset_id", fmt.Sprintf("%s/%s", testPoolName, name)),
+					testAccCheckTruenasDatasetResourceExists(resourceName, &dataset),
+				),
+			},
+			{
+				Config: testAccCheckResourceTruenasDatasetRenameConfig(testPoolName, parent, name+"-renamed", "truenas_dataset.parent.name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "dataset_id", fmt.Sprintf("%s/%s/%s-renamed", testPoolName, parent, name)),
+					resource.TestCheckResourceAttr(resourceName, "parent", parent),
+					testAccCheckTruenasDatasetResourceExists(resourceName, &dataset),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckResourceTruenasDatasetRenameConfig(pool string, parent string, name string, parentRef string) string {
+	if parentRef == "" {
+		parentRef = `""`
+	}
+
+	return fmt.Sprintf(`
+	resource "truenas_dataset" "parent" {
+		name = "%s"
+		pool = "%s"
+	}
+
+	resource "truenas_dataset" "test" {
+		name = "%s"
+		pool = "%s"
+		parent = %s
+		comments = "renamed in place"
+	}
+	`, parent, pool, name, pool, parentRef)
+}
+
 func testAccCheckResourceTruenasDatasetDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*api.APIClient)
 
@@ -85,6 +187,7 @@ func Test_datasetPathString(t *testing.T) {
 		{path: datasetPath{Pool: "Tank", Parent: "", Name: "Test"}, expected: "Tank/Test"},
 		{path: datasetPath{Pool: "Tank", Parent: "//home/sub//", Name: "Test"}, expected: "Tank/home/sub/Test"},
 		{path: datasetPath{Pool: "TankV2", Parent: "/home/", Name: "Test"}, expected: "TankV2/home/Test"},
+		{path: datasetPath{Pool: "Tank"}, expected: "Tank"},
 	}
 
 	for _, c := range testcases {
@@ -102,6 +205,7 @@ func Test_newDatasetPath(t *testing.T) {
 		{expected: datasetPath{Pool: "Tank", Parent: "", Name: "Test"}, path: "Tank/Test"},
 		{expected: datasetPath{Pool: "Tank", Parent: "home/sub", Name: "Test"}, path: "Tank/home/sub/Test"},
 		{expected: datasetPath{Pool: "TankV2", Parent: "home", Name: "Test"}, path: "TankV2/home/Test"},
+		{expected: datasetPath{Pool: "Tank"}, path: "Tank"},
 	}
 
 	for _, c := range testcases {
@@ -110,6 +214,304 @@ func Test_newDatasetPath(t *testing.T) {
 	}
 }
 
+func Test_flattenUserProperties(t *testing.T) {
+	// SCALE
+	scale := &api.Dataset{
+		AdditionalProperties: map[string]interface{}{
+			"user_properties": map[string]interface{}{
+				"com.example:owner":       map[string]interface{}{"value": "ops", "rawvalue": "ops", "source": "LOCAL"},
+				"com.example:tier":        map[string]interface{}{"value": "gold", "rawvalue": "gold", "source": "INHERITED"},
+				"org.freenas:description": map[string]interface{}{"value": "comment", "rawvalue": "comment", "source": "LOCAL"},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"com.example:owner": "ops"}, flattenUserProperties(scale))
+
+	// CORE
+	core := &api.Dataset{
+		AdditionalProperties: map[string]interface{}{
+			"com.example:owner":        map[string]interface{}{"value": "ops", "rawvalue": "ops", "source": "LOCAL"},
+			"special_small_block_size": map[string]interface{}{"value": "0", "rawvalue": "0", "source": "DEFAULT"},
+		},
+	}
+
+	assert.Equal(t, map[string]string{"com.example:owner": "ops"}, flattenUserProperties(core))
+}
+
+func Test_expandUserPropertiesUpdate(t *testing.T) {
+	o := map[string]interface{}{"com.example:owner": "ops", "com.example:tier": "gold", "com.example:keep": "yes"}
+	n := map[string]interface{}{"com.example:owner": "dev", "com.example:keep": "yes", "com.example:new": "1"}
+
+	assert.ElementsMatch(t, []map[string]interface{}{
+		{"key": "com.example:owner", "value": "dev"},
+		{"key": "com.example:new", "value": "1"},
+		{"key": "com.example:tier", "remove": true},
+	}, expandUserPropertiesUpdate(o, n))
+}
+
+func Test_validateUserProperties(t *testing.T) {
+	_, errs := validateUserProperties(map[string]interface{}{"com.example:owner": "ops"}, "user_properties")
+	assert.Empty(t, errs)
+
+	_, errs = validateUserProperties(map[string]interface{}{"owner": "ops"}, "user_properties")
+	assert.Len(t, errs, 1)
+
+	_, errs = validateUserProperties(map[string]interface{}{"org.truenas:managedby": "ops"}, "user_properties")
+	assert.Len(t, errs, 1)
+}
+
+func Test_flattenInheritableProperty(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASDataset().Schema, map[string]interface{}{
+		"pool":        "Tank",
+		"name":        "Test",
+		"compression": "inherit",
+		"atime":       "on",
+		"sync":        "INHERIT",
+	})
+
+	inherited := &api.CompositeValue{Value: getStringPtr("LZ4"), Source: getStringPtr("INHERITED")}
+	local := &api.CompositeValue{Value: getStringPtr("ZSTD"), Source: getStringPtr("LOCAL")}
+
+	assert.Equal(t, "inherit", flattenInheritableProperty(d, "compression", inherited))
+	assert.Equal(t, "zstd", flattenInheritableProperty(d, "compression", local))
+	assert.Equal(t, "lz4", flattenInheritableProperty(d, "atime", inherited))
+	// configured spelling is kept to avoid diffs
+	assert.Equal(t, "INHERIT", flattenInheritableProperty(d, "sync", inherited))
+}
+
+func Test_withInherit(t *testing.T) {
+	validate := validation.StringInSlice(withInherit([]string{"on", "off"}), false)
+
+	for _, v := range []string{"on", "inherit", "INHERIT"} {
+		_, errs := validate(v, "atime")
+		assert.Empty(t, errs, v)
+	}
+
+	_, errs := validate("Inherited", "atime")
+	assert.NotEmpty(t, errs)
+}
+
+func Test_flattenPropertySources(t *testing.T) {
+	sources := flattenPropertySources(map[string]*api.CompositeValue{
+		"compression": {Value: getStringPtr("LZ4"), Source: getStringPtr("INHERITED")},
+		"sync":        {Value: getStringPtr("STANDARD"), Source: getStringPtr("LOCAL")},
+		"atime":       nil,
+	})
+
+	assert.Equal(t, map[string]string{"compression": "INHERITED", "sync": "LOCAL"}, sources)
+}
+
+func Test_flattenDatasetChildren(t *testing.T) {
+	managed := map[string]interface{}{
+		managedUserProperty: map[string]interface{}{"value": "true", "source": "LOCAL"},
+	}
+
+	resp := &api.Dataset{
+		AdditionalProperties: map[string]interface{}{
+			"children": []interface{}{
+				map[string]interface{}{"id": "Tank/apps/plex"},
+				map[string]interface{}{
+					"id":              "Tank/apps/minio",
+					"user_properties": managed,
+					"children": []interface{}{
+						map[string]interface{}{
+							"id": "Tank/apps/minio/data",
+							"user_properties": map[string]interface{}{
+								managedUserProperty: map[string]interface{}{"value": "true", "source": "INHERITED"},
+							},
+						},
+					},
+				},
+				// CORE returns user properties as top level attributes
+				map[string]interface{}{
+					"id":                "Tank/apps/nextcloud",
+					managedUserProperty: map[string]interface{}{"value": "true", "source": "LOCAL"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"Tank/apps/plex", "Tank/apps/minio/data"}, flattenDatasetChildren(resp))
+	assert.Empty(t, flattenDatasetChildren(&api.Dataset{}))
+}
+
+func Test_checkDatasetDeletionProtection(t *testing.T) {
+	assert.NoError(t, checkDatasetDeletionProtection("Tank/apps", 98304, nil, 1048576))
+	assert.Error(t, checkDatasetDeletionProtection("Tank/apps", 98304, []string{"Tank/apps/plex"}, 1048576))
+	assert.Error(t, checkDatasetDeletionProtection("Tank/apps", 2147483648, nil, 1048576))
+}
+
+func Test_setDatasetSpaceUsage(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASDataset().Schema, map[string]interface{}{
+		"pool": "Tank",
+		"name": "Test",
+	})
+
+	resp := &api.Dataset{
+		Used:      &api.CompositeValue{Rawvalue: "3145728"},
+		Available: &api.CompositeValue{Rawvalue: "1073741824"},
+		AdditionalProperties: map[string]interface{}{
+			"usedbysnapshots": map[string]interface{}{"rawvalue": "1048576"},
+			"usedbychildren":  map[string]interface{}{"rawvalue": "0"},
+			"referenced":      map[string]interface{}{"rawvalue": "2097152"},
+			"compressratio":   map[string]interface{}{"value": "1.52x", "rawvalue": "1.52"},
+		},
+	}
+
+	assert.NoError(t, setDatasetSpaceUsage(resp, d))
+	assert.Equal(t, 3145728, d.Get("used_bytes"))
+	assert.Equal(t, 1073741824, d.Get("available_bytes"))
+	assert.Equal(t, 1048576, d.Get("used_by_snapshots_bytes"))
+	assert.Equal(t, 0, d.Get("used_by_children_bytes"))
+	assert.Equal(t, 2097152, d.Get("referenced_bytes"))
+	assert.Equal(t, 1.52, d.Get("compress_ratio"))
+}
+
+func Test_expandZFSDatasetProperty(t *testing.T) {
+	assert.Equal(t, "SA", expandZFSDatasetProperty("xattr", "sa"))
+	assert.Equal(t, "INHERIT", expandZFSDatasetProperty("log_bias", "inherit"))
+	assert.Equal(t, "INHERIT", expandZFSDatasetProperty("log_bias", "INHERIT"))
+	assert.Equal(t, 65536, expandZFSDatasetProperty("special_small_block_size", "64K"))
+	assert.Equal(t, 0, expandZFSDatasetProperty("special_small_block_size", "0"))
+	assert.Equal(t, "INHERIT", expandZFSDatasetProperty("special_small_block_size", "inherit"))
+}
+
+func Test_flattenZFSDatasetProperty(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASDataset().Schema, map[string]interface{}{
+		"pool":                     "Tank",
+		"name":                     "Test",
+		"special_small_block_size": "inherit",
+	})
+
+	resp := &api.Dataset{
+		AdditionalProperties: map[string]interface{}{
+			"special_small_block_size": map[string]interface{}{"value": "64K", "rawvalue": "65536", "source": "INHERITED"},
+			"dnodesize":                map[string]interface{}{"value": "AUTO", "rawvalue": "auto", "source": "LOCAL"},
+		},
+	}
+
+	assert.Equal(t, "inherit", flattenZFSDatasetProperty(d, "special_small_block_size", getZFSDatasetProperty(resp, "special_small_block_size")))
+	assert.Equal(t, "auto", flattenZFSDatasetProperty(d, "dnode_size", getZFSDatasetProperty(resp, "dnodesize")))
+	assert.Nil(t, getZFSDatasetProperty(resp, "logbias"))
+
+	d.Set("special_small_block_size", "0")
+	assert.Equal(t, "64K", flattenZFSDatasetProperty(d, "special_small_block_size", getZFSDatasetProperty(resp, "special_small_block_size")))
+}
+
+func Test_sumDatasetCommitments(t *testing.T) {
+	datasets := []api.Dataset{
+		{Id: "Tank", Quota: &api.CompositeValue{Rawvalue: "0"}},
+		{Id: "Tank/apps", Quota: &api.CompositeValue{Rawvalue: "1000"}},
+		{Id: "Tank/apps/plex", Quota: &api.CompositeValue{Rawvalue: "500"}},
+		{Id: "Tank/media", Quota: &api.CompositeValue{Rawvalue: "0"}},
+		{Id: "Tank/media/movies", Refquota: &api.CompositeValue{Rawvalue: "300"}, Reservation: &api.CompositeValue{Rawvalue: "200"}},
+		{Id: "Tank/vm", Volsize: &api.CompositeValue{Rawvalue: "400"}, Refreservation: &api.CompositeValue{Rawvalue: "450"}},
+		{Id: "Tank2/other", Quota: &api.CompositeValue{Rawvalue: "9999"}},
+	}
+
+	assert.Equal(t, 1750, sumDatasetCommitments(datasets, "Tank", nil))
+
+	// planned quota of a new dataset and a nested one already covered by its parent
+	assert.Equal(t, 2750, sumDatasetCommitments(datasets, "Tank", map[string]int{"Tank/backups": 1000, "Tank/apps/minio": 800}))
+
+	// planned values override current ones
+	assert.Equal(t, 1250, sumDatasetCommitments(datasets, "Tank", map[string]int{"Tank/apps": 0}))
+}
+
+func Test_checkPoolCapacity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// children are only returned nested under the pool root
+		fmt.Fprint(w, `[{"id": "Tank", "name": "Tank", "pool": "Tank", "type": "FILESYSTEM",
+			"used": {"rawvalue": "1000"}, "available": {"rawvalue": "1000"},
+			"children": [
+				{"id": "Tank/apps", "name": "Tank/apps", "pool": "Tank", "type": "FILESYSTEM", "quota": {"rawvalue": "1500"}, "children": []},
+				{"id": "Tank/vm", "name": "Tank/vm", "pool": "Tank", "type": "VOLUME", "volsize": {"rawvalue": "1000"}, "children": []}
+			]}]`)
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL)
+
+	msg, err := checkPoolCapacity(context.Background(), c, "Tank", nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, msg, "add up to 2500 bytes, more than the 2000 bytes")
+
+	msg, err = checkPoolCapacity(context.Background(), c, "Tank", map[string]int{"Tank/apps": 500})
+
+	assert.NoError(t, err)
+	assert.Empty(t, msg)
+
+	_, err = checkPoolCapacity(context.Background(), c, "Other", nil)
+
+	assert.Error(t, err)
+}
+
+func Test_resourceTrueNASDatasetCustomizeDiff_poolCapacity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pool/dataset" {
+			// skip property support check
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, `[{"id": "Tank", "name": "Tank", "pool": "Tank", "type": "FILESYSTEM",
+			"used": {"rawvalue": "1000"}, "available": {"rawvalue": "1000"},
+			"children": [
+				{"id": "Tank/apps", "name": "Tank/apps", "pool": "Tank", "type": "FILESYSTEM", "quota": {"rawvalue": "1500"}, "children": []}
+			]}]`)
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL)
+	r := resourceTrueNASDataset()
+
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":        "Tank",
+		"name":        "backups",
+		"quota_bytes": 1000,
+	}), c)
+
+	assert.ErrorContains(t, err, "add up to 2500 bytes, more than the 2000 bytes")
+
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":                  "Tank",
+		"name":                  "backups",
+		"quota_bytes":           1000,
+		"allow_pool_overcommit": true,
+	}), c)
+
+	assert.NoError(t, err)
+
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":        "Tank",
+		"name":        "backups",
+		"quota_bytes": 500,
+	}), c)
+
+	assert.NoError(t, err)
+
+	// shrinking quota of an overcommitted pool is allowed
+	state := &terraform.InstanceState{
+		ID: "Tank/apps",
+		Attributes: map[string]string{
+			"id":          "Tank/apps",
+			"pool":        "Tank",
+			"name":        "apps",
+			"quota_bytes": "3000",
+		},
+	}
+
+	_, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":        "Tank",
+		"name":        "apps",
+		"quota_bytes": 2500,
+	}), c)
+
+	assert.NoError(t, err)
+}
+
 func testAccCheckResourceTruenasDatasetConfig(pool string, name string) string {
 	return fmt.Sprintf(`
 	resource "truenas_dataset" "test" {
@@ -132,6 +534,9 @@ func testAccCheckResourceTruenasDatasetConfig(pool string, name string) string {
 		readonly = "off"
 		record_size = "256K"
 		case_sensitivity = "mixed"
+		user_properties = {
+			"com.example:owner" = "terraform"
+		}
 	}
 	`, name, pool)
 }
@@ -163,3 +568,41 @@ func testAccCheckTruenasDatasetResourceExists(n string, dataset *api.Dataset) re
 		return nil
 	}
 }
+
+func Test_resourceTrueNASDatasetCustomizeDiff_rename(t *testing.T) {
+	r := resourceTrueNASDataset()
+	state := &terraform.InstanceState{
+		ID: "Tank/media/old",
+		Attributes: map[string]string{
+			"id":          "Tank/media/old",
+			"dataset_id":  "Tank/media/old",
+			"pool":        "Tank",
+			"parent":      "media",
+			"name":        "old",
+			"mount_point": "/mnt/Tank/media/old",
+		},
+	}
+
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":   "Tank",
+		"parent": "media",
+		"name":   "new",
+	}), nil)
+
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	assert.True(t, diff.Attributes["dataset_id"].NewComputed)
+	assert.True(t, diff.Attributes["mount_point"].NewComputed)
+
+	// unchanged path keeps known values
+	diff, err = r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":     "Tank",
+		"parent":   "media",
+		"name":     "old",
+		"comments": "media files",
+	}), nil)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, diff.Attributes, "dataset_id")
+	assert.NotContains(t, diff.Attributes, "mount_point")
+}
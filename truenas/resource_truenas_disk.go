@@ -0,0 +1,213 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+var diskHddStandbyValues = []string{"ALWAYS ON", "5", "10", "20", "30", "60", "120", "180", "240", "300", "330"}
+var diskAdvPowerMgmtValues = []string{"DISABLED", "1", "64", "127", "128", "192", "254"}
+
+type diskUpdate struct {
+	Description   string `json:"description"`
+	Hddstandby    string `json:"hddstandby"`
+	Advpowermgmt  string `json:"advpowermgmt"`
+	Togglesmart   bool   `json:"togglesmart"`
+	Smartoptions  string `json:"smartoptions"`
+	Critical      *int   `json:"critical"`
+	Difference    *int   `json:"difference"`
+	Informational *int   `json:"informational"`
+}
+
+func resourceTrueNASDisk() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Per-disk settings of a disk attached to TrueNAS. Disks cannot be created, destroying the resource only removes it from the state.",
+		CreateContext: resourceTrueNASDiskCreate,
+		ReadContext:   resourceTrueNASDiskRead,
+		UpdateContext: resourceTrueNASDiskUpdate,
+		DeleteContext: resourceTrueNASDiskDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"identifier": &schema.Schema{
+				Description: "Stable disk identifier, eg. `{serial}WD-WCC4N0123456`, see `truenas_disks` data source",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": &schema.Schema{
+				Description: "Device name, eg. `ada0` or `sda`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"serial": &schema.Schema{
+				Description: "Serial number",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Disk description",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"hddstandby": &schema.Schema{
+				Description:  "Minutes of inactivity before the drive enters standby mode, `ALWAYS ON` disables standby",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ALWAYS ON",
+				ValidateFunc: validation.StringInSlice(diskHddStandbyValues, false),
+			},
+			"advpowermgmt": &schema.Schema{
+				Description:  "Advanced Power Management level, `DISABLED` or `1` (lowest power) to `254` (highest performance)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "DISABLED",
+				ValidateFunc: validation.StringInSlice(diskAdvPowerMgmtValues, false),
+			},
+			"togglesmart": &schema.Schema{
+				Description: "Enable S.M.A.R.T. tests for this disk",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"smartoptions": &schema.Schema{
+				Description: "Additional smartctl options",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"critical": &schema.Schema{
+				Description:  "Report critical message when temperature reaches this many degrees Celsius, `0` uses S.M.A.R.T. service setting",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"difference": &schema.Schema{
+				Description:  "Report temperature changes of this many degrees Celsius since last check, `0` uses S.M.A.R.T. service setting",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"informational": &schema.Schema{
+				Description:  "Report informational message when temperature reaches this many degrees Celsius, `0` uses S.M.A.R.T. service setting",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+	}
+}
+
+func diskPath(identifier string) string {
+	return fmt.Sprintf("/disk/id/%s", url.PathEscape(identifier))
+}
+
+func resourceTrueNASDiskRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var dsk disk
+
+	resp, err := callAPI(ctx, c, http.MethodGet, diskPath(d.Id()), nil, &dsk)
+
+	if err != nil {
+		// disk was removed from the system
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting disk: %s", err)
+	}
+
+	d.Set("identifier", dsk.Identifier)
+	d.Set("name", dsk.Name)
+	d.Set("serial", dsk.Serial)
+	d.Set("description", dsk.Description)
+	d.Set("hddstandby", dsk.Hddstandby)
+	d.Set("advpowermgmt", dsk.Advpowermgmt)
+	d.Set("togglesmart", dsk.Togglesmart)
+	d.Set("smartoptions", dsk.Smartoptions)
+	d.Set("critical", flattenDiskTemperature(dsk.Critical))
+	d.Set("difference", flattenDiskTemperature(dsk.Difference))
+	d.Set("informational", flattenDiskTemperature(dsk.Informational))
+
+	return diags
+}
+
+func resourceTrueNASDiskCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	identifier := d.Get("identifier").(string)
+
+	log.Printf("[DEBUG] Updating TrueNAS disk: %s", identifier)
+
+	_, err := callAPI(ctx, c, http.MethodPut, diskPath(identifier), expandDisk(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating disk: %s", err)
+	}
+
+	d.SetId(identifier)
+
+	return resourceTrueNASDiskRead(ctx, d, m)
+}
+
+func resourceTrueNASDiskUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, diskPath(d.Id()), expandDisk(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating disk: %s", err)
+	}
+
+	return resourceTrueNASDiskRead(ctx, d, m)
+}
+
+func resourceTrueNASDiskDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	log.Printf("[DEBUG] Removing TrueNAS disk %s from state, settings are left unchanged", d.Id())
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandDisk(d *schema.ResourceData) diskUpdate {
+	return diskUpdate{
+		Description:   d.Get("description").(string),
+		Hddstandby:    d.Get("hddstandby").(string),
+		Advpowermgmt:  d.Get("advpowermgmt").(string),
+		Togglesmart:   d.Get("togglesmart").(bool),
+		Smartoptions:  d.Get("smartoptions").(string),
+		Critical:      expandDiskTemperature(d.Get("critical").(int)),
+		Difference:    expandDiskTemperature(d.Get("difference").(int)),
+		Informational: expandDiskTemperature(d.Get("informational").(int)),
+	}
+}
+
+// expandDiskTemperature maps 0 to null, which makes the disk use S.M.A.R.T. service setting
+func expandDiskTemperature(v int) *int {
+	if v == 0 {
+		return nil
+	}
+
+	return &v
+}
+
+func flattenDiskTemperature(v *int) int {
+	if v == nil {
+		return 0
+	}
+
+	return *v
+}
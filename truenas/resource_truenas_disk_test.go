@@ -0,0 +1,34 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandDisk(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASDisk().Schema, map[string]interface{}{
+		"identifier": "{serial}A",
+		"hddstandby": "60",
+		"critical":   50,
+	})
+
+	b, err := json.Marshal(expandDisk(d))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"description": "",
+		"hddstandby": "60",
+		"advpowermgmt": "DISABLED",
+		"togglesmart": true,
+		"smartoptions": "",
+		"critical": 50,
+		"difference": null,
+		"informational": null
+	}`, string(b))
+}
+
+func Test_diskPath(t *testing.T) {
+	assert.Equal(t, "/disk/id/%7Bserial%7DWD-123", diskPath("{serial}WD-123"))
+}
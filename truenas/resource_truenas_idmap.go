@@ -0,0 +1,423 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// idmap domains TrueNAS creates for directory services
+const (
+	idmapActiveDirectory = "DS_TYPE_ACTIVEDIRECTORY"
+	idmapLDAP            = "DS_TYPE_LDAP"
+)
+
+var idmapBackends = []string{"AD", "AUTORID", "LDAP", "NSS", "RFC2307", "RID", "TDB"}
+
+type idmapBackendOptions struct {
+	required []string
+	optional []string
+}
+
+// options accepted by each idmap backend, see idmap.backend_options
+var idmapOptions = map[string]idmapBackendOptions{
+	"AD": {
+		required: []string{"schema_mode"},
+		optional: []string{"unix_primary_group", "unix_nss_info"},
+	},
+	"AUTORID": {
+		optional: []string{"rangesize", "readonly", "ignore_builtin"},
+	},
+	"LDAP": {
+		required: []string{"ldap_base_dn", "ldap_url"},
+		optional: []string{"ldap_user_dn", "ldap_user_dn_password", "readonly", "validate_certificates"},
+	},
+	"NSS": {
+		optional: []string{"linked_service"},
+	},
+	"RFC2307": {
+		required: []string{"ldap_server"},
+		optional: []string{"ldap_realm", "bind_path_user", "bind_path_group", "user_cn", "cn_realm", "ldap_domain", "ldap_url", "ldap_user_dn", "ldap_user_dn_password", "ssl", "validate_certificates"},
+	},
+	"RID": {
+		optional: []string{"sssd_compat"},
+	},
+	"TDB": {},
+}
+
+// idmap options that hold secrets, these are only accepted in secret_options
+var idmapSecretOptions = []string{"ldap_user_dn_password"}
+
+// idmap options that TrueNAS expects as integers
+var idmapIntOptions = []string{"rangesize"}
+
+type idmapDomain struct {
+	ID            int                    `json:"id,omitempty"`
+	Name          string                 `json:"name"`
+	DNSDomainName *string                `json:"dns_domain_name"`
+	RangeLow      int                    `json:"range_low"`
+	RangeHigh     int                    `json:"range_high"`
+	IdmapBackend  string                 `json:"idmap_backend"`
+	Certificate   *int                   `json:"certificate"`
+	Options       map[string]interface{} `json:"options"`
+}
+
+func resourceTrueNASIdmap() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Idmap domains map Windows SIDs of a domain to UIDs and GIDs on TrueNAS",
+		CreateContext: resourceTrueNASIdmapCreate,
+		ReadContext:   resourceTrueNASIdmapRead,
+		UpdateContext: resourceTrueNASIdmapUpdate,
+		DeleteContext: resourceTrueNASIdmapDelete,
+		CustomizeDiff: resourceTrueNASIdmapCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"idmap_id": &schema.Schema{
+				Description: "Idmap domain ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"name": &schema.Schema{
+				Description: "Short (pre-Windows 2000) domain name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"dns_domain_name": &schema.Schema{
+				Description: "DNS name of the domain",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"range_low": &schema.Schema{
+				Description:  "Lowest UID/GID of the range, must not overlap with other idmap domains",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(1000, 2147483647),
+			},
+			"range_high": &schema.Schema{
+				Description:  "Highest UID/GID of the range, must not overlap with other idmap domains",
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(1000, 2147483647),
+			},
+			"idmap_backend": &schema.Schema{
+				Description:  "Idmap backend: " + strings.Join(idmapBackends, ", "),
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(idmapBackends, false),
+			},
+			"certificate": &schema.Schema{
+				Description: "Certificate ID used for LDAP connections",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"options": &schema.Schema{
+				Description: "Backend specific options, eg. `schema_mode` for `AD`, `rangesize` for `AUTORID` or `ldap_url` for `LDAP`",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret_options": &schema.Schema{
+				Description: "Backend specific secret options: `ldap_user_dn_password`",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceTrueNASIdmapRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var domain idmapDomain
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/idmap/id/%d", id), nil, &domain)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting idmap: %s", err)
+	}
+
+	// name is only unknown when resource is being imported
+	importing := d.Get("name").(string) == ""
+
+	d.Set("idmap_id", domain.ID)
+	d.Set("name", domain.Name)
+	d.Set("range_low", domain.RangeLow)
+	d.Set("range_high", domain.RangeHigh)
+	d.Set("idmap_backend", domain.IdmapBackend)
+
+	if domain.DNSDomainName != nil {
+		d.Set("dns_domain_name", *domain.DNSDomainName)
+	} else {
+		d.Set("dns_domain_name", nil)
+	}
+
+	if domain.Certificate != nil {
+		d.Set("certificate", *domain.Certificate)
+	} else {
+		d.Set("certificate", nil)
+	}
+
+	options, secrets := splitAttributes(
+		domain.Options,
+		idmapSecretOptions,
+		d.Get("options").(map[string]interface{}),
+		d.Get("secret_options").(map[string]interface{}),
+		importing,
+	)
+
+	if err := d.Set("options", options); err != nil {
+		return diag.Errorf("error setting options: %s", err)
+	}
+
+	if err := d.Set("secret_options", secrets); err != nil {
+		return diag.Errorf("error setting secret_options: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASIdmapCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandIdmap(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS idmap: %s", input.Name)
+
+	var domain idmapDomain
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/idmap", input, &domain)
+
+	if err != nil {
+		return diag.Errorf("error creating idmap: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(domain.ID))
+
+	log.Printf("[INFO] TrueNAS idmap (%s) created", d.Id())
+
+	return resourceTrueNASIdmapRead(ctx, d, m)
+}
+
+func resourceTrueNASIdmapUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/idmap/id/%s", d.Id()), expandIdmap(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating idmap: %s", err)
+	}
+
+	return resourceTrueNASIdmapRead(ctx, d, m)
+}
+
+func resourceTrueNASIdmapDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS idmap: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/idmap/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting idmap: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS idmap (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+// resourceTrueNASIdmapCustomizeDiff validates backend options and makes sure
+// the range does not overlap with any other idmap domain before anything is submitted
+func resourceTrueNASIdmapCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	backend := d.Get("idmap_backend").(string)
+
+	if d.NewValueKnown("idmap_backend") && d.NewValueKnown("options") && d.NewValueKnown("secret_options") {
+		options := map[string]interface{}{}
+
+		for k, v := range d.Get("options").(map[string]interface{}) {
+			if containsString(idmapSecretOptions, k) {
+				return fmt.Errorf("option %q holds a secret, set it in secret_options instead", k)
+			}
+			options[k] = v
+		}
+
+		for k, v := range d.Get("secret_options").(map[string]interface{}) {
+			options[k] = v
+		}
+
+		if err := validateIdmapOptions(backend, options); err != nil {
+			return err
+		}
+	}
+
+	if !d.NewValueKnown("range_low") || !d.NewValueKnown("range_high") {
+		return nil
+	}
+
+	low := d.Get("range_low").(int)
+	high := d.Get("range_high").(int)
+
+	if low >= high {
+		return fmt.Errorf("range_low (%d) must be lower than range_high (%d)", low, high)
+	}
+
+	if d.Id() != "" && !d.HasChanges("range_low", "range_high") {
+		return nil
+	}
+
+	c := m.(*api.APIClient)
+
+	var domains []idmapDomain
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/idmap", nil, &domains)
+
+	if err != nil {
+		return fmt.Errorf("error getting idmap domains: %s", err)
+	}
+
+	id, _ := strconv.Atoi(d.Id())
+
+	if other := findIdmapRangeOverlap(domains, id, low, high); other != nil {
+		return fmt.Errorf("range %d-%d overlaps with idmap domain %q (%d-%d)", low, high, other.Name, other.RangeLow, other.RangeHigh)
+	}
+
+	return nil
+}
+
+// validateIdmapOptions checks that all required options of the backend are set and no unknown ones are used
+func validateIdmapOptions(backend string, options map[string]interface{}) error {
+	allowed, ok := idmapOptions[backend]
+
+	if !ok {
+		return fmt.Errorf("unsupported idmap backend: %s", backend)
+	}
+
+	for _, k := range allowed.required {
+		if _, ok := options[k]; !ok {
+			return fmt.Errorf("option %q is required for %s idmap backend", k, backend)
+		}
+	}
+
+	var unknown []string
+
+	for k := range options {
+		if !containsString(allowed.required, k) && !containsString(allowed.optional, k) {
+			unknown = append(unknown, k)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("options %s are not supported by %s idmap backend", strings.Join(unknown, ", "), backend)
+	}
+
+	return nil
+}
+
+// findIdmapRangeOverlap returns first domain, other than the one with given id, whose range overlaps low-high
+func findIdmapRangeOverlap(domains []idmapDomain, id int, low int, high int) *idmapDomain {
+	for i, domain := range domains {
+		if domain.ID == id {
+			continue
+		}
+
+		if low <= domain.RangeHigh && domain.RangeLow <= high {
+			return &domains[i]
+		}
+	}
+
+	return nil
+}
+
+func expandIdmap(d *schema.ResourceData) idmapDomain {
+	domain := idmapDomain{
+		Name:         d.Get("name").(string),
+		RangeLow:     d.Get("range_low").(int),
+		RangeHigh:    d.Get("range_high").(int),
+		IdmapBackend: d.Get("idmap_backend").(string),
+		Options:      map[string]interface{}{},
+	}
+
+	if name, ok := d.GetOk("dns_domain_name"); ok {
+		domain.DNSDomainName = getStringPtr(name.(string))
+	}
+
+	if cert, ok := d.GetOk("certificate"); ok {
+		v := cert.(int)
+		domain.Certificate = &v
+	}
+
+	for k, v := range d.Get("options").(map[string]interface{}) {
+		domain.Options[k] = expandAttributeValue(k, v.(string), idmapIntOptions)
+	}
+
+	for k, v := range d.Get("secret_options").(map[string]interface{}) {
+		domain.Options[k] = expandAttributeValue(k, v.(string), idmapIntOptions)
+	}
+
+	return domain
+}
+
+func getIdmapDomain(ctx context.Context, c *api.APIClient, name string) (*idmapDomain, error) {
+	var domains []idmapDomain
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/idmap?name="+url.QueryEscape(name), nil, &domains)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting idmap %s: %s", name, err)
+	}
+
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("idmap %s not found", name)
+	}
+
+	return &domains[0], nil
+}
+
+func updateIdmapBackend(ctx context.Context, c *api.APIClient, name string, backend string) error {
+	domain, err := getIdmapDomain(ctx, c, name)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/idmap/id/%d", domain.ID), map[string]interface{}{"idmap_backend": backend}, nil)
+
+	if err != nil {
+		return fmt.Errorf("error updating idmap %s: %s", name, err)
+	}
+
+	return nil
+}
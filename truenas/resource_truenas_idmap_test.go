@@ -0,0 +1,102 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestAccResourceTruenasIdmap_basic(t *testing.T) {
+	name := strings.ToUpper(acctest.RandStringFromCharSet(8, acctest.CharSetAlpha))
+	resourceName := "truenas_idmap.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceTruenasIdmapDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasIdmapConfig(name, 200000001, 200100000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "idmap_backend", "AUTORID"),
+					resource.TestCheckResourceAttr(resourceName, "range_low", "200000001"),
+					resource.TestCheckResourceAttr(resourceName, "range_high", "200100000"),
+					resource.TestCheckResourceAttr(resourceName, "options.rangesize", "10000"),
+					resource.TestCheckResourceAttrSet(resourceName, "idmap_id"),
+				),
+			},
+			{
+				// overlaps with default DS_TYPE_ACTIVEDIRECTORY range
+				Config:      testAccCheckResourceTruenasIdmapConfig(name, 100000001, 200000000),
+				ExpectError: regexp.MustCompile("overlaps with idmap domain"),
+			},
+		},
+	})
+}
+
+func Test_validateIdmapOptions(t *testing.T) {
+	assert.NoError(t, validateIdmapOptions("AD", map[string]interface{}{"schema_mode": "RFC2307", "unix_nss_info": "true"}))
+	assert.NoError(t, validateIdmapOptions("TDB", map[string]interface{}{}))
+	assert.EqualError(t, validateIdmapOptions("AD", map[string]interface{}{}), "option \"schema_mode\" is required for AD idmap backend")
+	assert.EqualError(t, validateIdmapOptions("RID", map[string]interface{}{"schema_mode": "RFC2307", "rangesize": "10000"}), "options rangesize, schema_mode are not supported by RID idmap backend")
+}
+
+func Test_findIdmapRangeOverlap(t *testing.T) {
+	domains := []idmapDomain{
+		{ID: 1, Name: "DS_TYPE_ACTIVEDIRECTORY", RangeLow: 100000001, RangeHigh: 200000000},
+		{ID: 2, Name: "DS_TYPE_LDAP", RangeLow: 10000, RangeHigh: 90000000},
+		{ID: 5, Name: "OFFICE", RangeLow: 200000001, RangeHigh: 300000000},
+	}
+
+	assert.Nil(t, findIdmapRangeOverlap(domains, 0, 300000001, 400000000))
+	assert.Equal(t, "OFFICE", findIdmapRangeOverlap(domains, 0, 250000000, 350000000).Name)
+	assert.Equal(t, "DS_TYPE_ACTIVEDIRECTORY", findIdmapRangeOverlap(domains, 0, 90000001, 100000001).Name)
+	// domain does not overlap with itself
+	assert.Nil(t, findIdmapRangeOverlap(domains, 5, 200000001, 300000000))
+}
+
+func testAccCheckResourceTruenasIdmapConfig(name string, low int, high int) string {
+	return fmt.Sprintf(`
+	resource "truenas_idmap" "test" {
+		name = "%s"
+		idmap_backend = "AUTORID"
+		range_low = %d
+		range_high = %d
+		options = {
+			rangesize = "10000"
+		}
+	}
+	`, name, low, high)
+}
+
+func testAccCheckResourceTruenasIdmapDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api.APIClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "truenas_idmap" {
+			continue
+		}
+
+		resp, err := callAPI(context.Background(), client, http.MethodGet, fmt.Sprintf("/idmap/id/%s", rs.Primary.ID), nil, nil)
+
+		if err == nil {
+			return fmt.Errorf("idmap (%s) still exists", rs.Primary.ID)
+		}
+
+		// check if error is in fact 404 (not found)
+		if resp == nil || resp.StatusCode != 404 {
+			return fmt.Errorf("Error occured while checking for absence of idmap (%s)", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
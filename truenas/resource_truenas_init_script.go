@@ -0,0 +1,217 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type initScript struct {
+	ID      int    `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Command string `json:"command"`
+	Script  string `json:"script"`
+	When    string `json:"when"`
+	Enabled bool   `json:"enabled"`
+	Timeout int    `json:"timeout"`
+	Comment string `json:"comment"`
+}
+
+func resourceTrueNASInitScript() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Init/shutdown scripts run commands or scripts when TrueNAS boots or shuts down.",
+		CreateContext: resourceTrueNASInitScriptCreate,
+		ReadContext:   resourceTrueNASInitScriptRead,
+		UpdateContext: resourceTrueNASInitScriptUpdate,
+		DeleteContext: resourceTrueNASInitScriptDelete,
+		CustomizeDiff: resourceTrueNASInitScriptCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"init_script_id": &schema.Schema{
+				Description: "Init script ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"type": &schema.Schema{
+				Description:  "`COMMAND` runs `command`, `SCRIPT` runs script file at `script` path",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"COMMAND", "SCRIPT"}, false),
+			},
+			"command": &schema.Schema{
+				Description:  "Command to run, required when type is `COMMAND`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCommand,
+			},
+			"script": &schema.Schema{
+				Description:  "Path to script file, required when type is `SCRIPT`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateScriptPath,
+			},
+			"when": &schema.Schema{
+				Description:  "When to run: `PREINIT` (early in boot process, before services start), `POSTINIT` (at the end of boot process) or `SHUTDOWN`",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"PREINIT", "POSTINIT", "SHUTDOWN"}, false),
+			},
+			"enabled": &schema.Schema{
+				Description: "`true` if init script is enabled",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"timeout": &schema.Schema{
+				Description:  "Seconds the command or script is allowed to run before it is stopped",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"comment": &schema.Schema{
+				Description: "Optional init script description",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASInitScriptRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var script initScript
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/initshutdownscript/id/%d", id), nil, &script)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting init script: %s", err)
+	}
+
+	d.Set("init_script_id", script.ID)
+	d.Set("type", script.Type)
+	d.Set("command", script.Command)
+	d.Set("script", script.Script)
+	d.Set("when", script.When)
+	d.Set("enabled", script.Enabled)
+	d.Set("timeout", script.Timeout)
+	d.Set("comment", script.Comment)
+
+	return diags
+}
+
+func resourceTrueNASInitScriptCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandInitScript(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS init script: %+v", input)
+
+	var script initScript
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/initshutdownscript", input, &script)
+
+	if err != nil {
+		return diag.Errorf("error creating init script: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(script.ID))
+
+	log.Printf("[INFO] TrueNAS init script (%s) created", d.Id())
+
+	return resourceTrueNASInitScriptRead(ctx, d, m)
+}
+
+func resourceTrueNASInitScriptUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/initshutdownscript/id/%s", d.Id()), expandInitScript(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating init script: %s", err)
+	}
+
+	return resourceTrueNASInitScriptRead(ctx, d, m)
+}
+
+func resourceTrueNASInitScriptDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS init script: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/initshutdownscript/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting init script: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS init script (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+// resourceTrueNASInitScriptCustomizeDiff makes sure the attribute matching type is set
+func resourceTrueNASInitScriptCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("command") || !d.NewValueKnown("script") {
+		return nil
+	}
+
+	return validateInitScriptType(d.Get("type").(string), d.Get("command").(string), d.Get("script").(string))
+}
+
+func validateInitScriptType(scriptType string, command string, script string) error {
+	switch scriptType {
+	case "COMMAND":
+		if command == "" {
+			return fmt.Errorf("command is required when type is COMMAND")
+		}
+		if script != "" {
+			return fmt.Errorf("script can't be set when type is COMMAND")
+		}
+	case "SCRIPT":
+		if script == "" {
+			return fmt.Errorf("script is required when type is SCRIPT")
+		}
+		if command != "" {
+			return fmt.Errorf("command can't be set when type is SCRIPT")
+		}
+	}
+
+	return nil
+}
+
+func expandInitScript(d *schema.ResourceData) initScript {
+	return initScript{
+		Type:    d.Get("type").(string),
+		Command: d.Get("command").(string),
+		Script:  d.Get("script").(string),
+		When:    d.Get("when").(string),
+		Enabled: d.Get("enabled").(bool),
+		Timeout: d.Get("timeout").(int),
+		Comment: d.Get("comment").(string),
+	}
+}
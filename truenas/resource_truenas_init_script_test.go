@@ -0,0 +1,83 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestAccResourceTruenasInitScript_basic(t *testing.T) {
+	resourceName := "truenas_init_script.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceTruenasInitScriptDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasInitScriptConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "type", "COMMAND"),
+					resource.TestCheckResourceAttr(resourceName, "command", "sysctl kern.ipc.somaxconn=2048"),
+					resource.TestCheckResourceAttr(resourceName, "when", "POSTINIT"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "timeout", "30"),
+					resource.TestCheckResourceAttr(resourceName, "comment", "tf init script"),
+					resource.TestCheckResourceAttrSet(resourceName, "init_script_id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_validateInitScriptType(t *testing.T) {
+	assert.NoError(t, validateInitScriptType("COMMAND", "ethtool -K eth0 tso off", ""))
+	assert.NoError(t, validateInitScriptType("SCRIPT", "", "/mnt/Tank/scripts/init.sh"))
+	assert.Error(t, validateInitScriptType("COMMAND", "", "/mnt/Tank/scripts/init.sh"))
+	assert.Error(t, validateInitScriptType("SCRIPT", "ethtool -K eth0 tso off", ""))
+	assert.Error(t, validateInitScriptType("SCRIPT", "", ""))
+}
+
+const testAccCheckResourceTruenasInitScriptConfig = `
+	resource "truenas_init_script" "test" {
+		type = "COMMAND"
+		command = "sysctl kern.ipc.somaxconn=2048"
+		when = "POSTINIT"
+		enabled = false
+		timeout = 30
+		comment = "tf init script"
+	}
+`
+
+func testAccCheckResourceTruenasInitScriptDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api.APIClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "truenas_init_script" {
+			continue
+		}
+
+		resp, err := callAPI(context.Background(), client, http.MethodGet, fmt.Sprintf("/initshutdownscript/id/%s", rs.Primary.ID), nil, nil)
+
+		if err == nil {
+			return fmt.Errorf("init script (%s) still exists", rs.Primary.ID)
+		}
+
+		// check if error is in fact 404 (not found)
+		if resp == nil || resp.StatusCode != 404 {
+			return fmt.Errorf("Error occured while checking for absence of init script (%s)", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type kerberosKeytab struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+	File string `json:"file"`
+}
+
+func resourceTrueNASKerberosKeytab() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Kerberos keytabs allow joining Active Directory or binding to LDAP without storing account passwords",
+		CreateContext: resourceTrueNASKerberosKeytabCreate,
+		ReadContext:   resourceTrueNASKerberosKeytabRead,
+		UpdateContext: resourceTrueNASKerberosKeytabUpdate,
+		DeleteContext: resourceTrueNASKerberosKeytabDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"keytab_id": &schema.Schema{
+				Description: "Kerberos keytab ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"name": &schema.Schema{
+				Description: "Keytab name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"file": &schema.Schema{
+				Description: "Base64 encoded keytab file, eg. `filebase64(\"nas.keytab\")`",
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASKerberosKeytabRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var keytab kerberosKeytab
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/kerberos/keytab/id/%d", id), nil, &keytab)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting kerberos keytab: %s", err)
+	}
+
+	d.Set("keytab_id", keytab.ID)
+	d.Set("name", keytab.Name)
+	d.Set("file", keytab.File)
+
+	return diags
+}
+
+func resourceTrueNASKerberosKeytabCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandKerberosKeytab(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS kerberos keytab: %s", input.Name)
+
+	var keytab kerberosKeytab
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/kerberos/keytab", input, &keytab)
+
+	if err != nil {
+		return diag.Errorf("error creating kerberos keytab: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(keytab.ID))
+
+	log.Printf("[INFO] TrueNAS kerberos keytab (%s) created", d.Id())
+
+	return resourceTrueNASKerberosKeytabRead(ctx, d, m)
+}
+
+func resourceTrueNASKerberosKeytabUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/kerberos/keytab/id/%s", d.Id()), expandKerberosKeytab(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating kerberos keytab: %s", err)
+	}
+
+	return resourceTrueNASKerberosKeytabRead(ctx, d, m)
+}
+
+func resourceTrueNASKerberosKeytabDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS kerberos keytab: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/kerberos/keytab/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting kerberos keytab: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS kerberos keytab (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandKerberosKeytab(d *schema.ResourceData) kerberosKeytab {
+	return kerberosKeytab{
+		Name: d.Get("name").(string),
+		File: d.Get("file").(string),
+	}
+}
@@ -0,0 +1,25 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandKerberosKeytab(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASKerberosKeytab().Schema, map[string]interface{}{
+		"name": "nas",
+		"file": "BQIAAABHAAIAC0VYQU1QTEUuQ09N",
+	})
+
+	keytab := expandKerberosKeytab(d)
+
+	assert.Equal(t, "nas", keytab.Name)
+	assert.Equal(t, "BQIAAABHAAIAC0VYQU1QTEUuQ09N", keytab.File)
+
+	body, err := json.Marshal(keytab)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "nas", "file": "BQIAAABHAAIAC0VYQU1QTEUuQ09N"}`, string(body))
+}
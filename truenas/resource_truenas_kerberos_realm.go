@@ -0,0 +1,172 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type kerberosRealm struct {
+	ID            int      `json:"id,omitempty"`
+	Realm         string   `json:"realm"`
+	KDC           []string `json:"kdc"`
+	AdminServer   []string `json:"admin_server"`
+	KpasswdServer []string `json:"kpasswd_server"`
+}
+
+func resourceTrueNASKerberosRealm() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Kerberos realms used by Active Directory, LDAP and NFS with Kerberos security",
+		CreateContext: resourceTrueNASKerberosRealmCreate,
+		ReadContext:   resourceTrueNASKerberosRealmRead,
+		UpdateContext: resourceTrueNASKerberosRealmUpdate,
+		DeleteContext: resourceTrueNASKerberosRealmDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"realm_id": &schema.Schema{
+				Description: "Kerberos realm ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"realm": &schema.Schema{
+				Description: "Kerberos realm name, eg. `EXAMPLE.COM`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"kdc": &schema.Schema{
+				Description: "Key distribution centers, discovered using DNS if not set",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"admin_server": &schema.Schema{
+				Description: "Kerberos admin servers",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"kpasswd_server": &schema.Schema{
+				Description: "Kerberos password servers",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func resourceTrueNASKerberosRealmRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var realm kerberosRealm
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/kerberos/realm/id/%d", id), nil, &realm)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting kerberos realm: %s", err)
+	}
+
+	d.Set("realm_id", realm.ID)
+	d.Set("realm", realm.Realm)
+
+	if err := d.Set("kdc", flattenStringList(realm.KDC)); err != nil {
+		return diag.Errorf("error setting kdc: %s", err)
+	}
+
+	if err := d.Set("admin_server", flattenStringList(realm.AdminServer)); err != nil {
+		return diag.Errorf("error setting admin_server: %s", err)
+	}
+
+	if err := d.Set("kpasswd_server", flattenStringList(realm.KpasswdServer)); err != nil {
+		return diag.Errorf("error setting kpasswd_server: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASKerberosRealmCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandKerberosRealm(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS kerberos realm: %s", input.Realm)
+
+	var realm kerberosRealm
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/kerberos/realm", input, &realm)
+
+	if err != nil {
+		return diag.Errorf("error creating kerberos realm: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(realm.ID))
+
+	log.Printf("[INFO] TrueNAS kerberos realm (%s) created", d.Id())
+
+	return resourceTrueNASKerberosRealmRead(ctx, d, m)
+}
+
+func resourceTrueNASKerberosRealmUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/kerberos/realm/id/%s", d.Id()), expandKerberosRealm(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating kerberos realm: %s", err)
+	}
+
+	return resourceTrueNASKerberosRealmRead(ctx, d, m)
+}
+
+func resourceTrueNASKerberosRealmDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS kerberos realm: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/kerberos/realm/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting kerberos realm: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS kerberos realm (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandKerberosRealm(d *schema.ResourceData) kerberosRealm {
+	return kerberosRealm{
+		Realm:         d.Get("realm").(string),
+		KDC:           expandStrings(d.Get("kdc").([]interface{})),
+		AdminServer:   expandStrings(d.Get("admin_server").([]interface{})),
+		KpasswdServer: expandStrings(d.Get("kpasswd_server").([]interface{})),
+	}
+}
@@ -0,0 +1,75 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAccResourceTruenasKerberosRealm_basic(t *testing.T) {
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlpha)
+	realm := strings.ToUpper(fmt.Sprintf("%s-%s.example.com", testResourcePrefix, suffix))
+	resourceName := "truenas_kerberos_realm.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckResourceTruenasKerberosRealmDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasKerberosRealmConfig(realm),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "realm", realm),
+					resource.TestCheckResourceAttr(resourceName, "kdc.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "kdc.0", "kdc1.example.com"),
+					resource.TestCheckResourceAttr(resourceName, "admin_server.0", "kdc1.example.com"),
+					resource.TestCheckResourceAttrSet(resourceName, "realm_id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckResourceTruenasKerberosRealmConfig(realm string) string {
+	return fmt.Sprintf(`
+	resource "truenas_kerberos_realm" "test" {
+		realm = "%s"
+		kdc = ["kdc1.example.com", "kdc2.example.com"]
+		admin_server = ["kdc1.example.com"]
+	}
+	`, realm)
+}
+
+func testAccCheckResourceTruenasKerberosRealmDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api.APIClient)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "truenas_kerberos_realm" {
+			continue
+		}
+
+		resp, err := callAPI(context.Background(), client, http.MethodGet, fmt.Sprintf("/kerberos/realm/id/%s", rs.Primary.ID), nil, nil)
+
+		if err == nil {
+			return fmt.Errorf("kerberos realm (%s) still exists", rs.Primary.ID)
+		}
+
+		// check if error is in fact 404 (not found)
+		if resp == nil || resp.StatusCode != 404 {
+			return fmt.Errorf("Error occured while checking for absence of kerberos realm (%s)", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
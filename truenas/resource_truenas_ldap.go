@@ -0,0 +1,342 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ldapConfig struct {
+	ID                   int      `json:"id,omitempty"`
+	Hostname             []string `json:"hostname"`
+	Basedn               string   `json:"basedn"`
+	Binddn               string   `json:"binddn"`
+	Bindpw               string   `json:"bindpw,omitempty"`
+	Anonbind             bool     `json:"anonbind"`
+	SSL                  string   `json:"ssl"`
+	Certificate          *int     `json:"certificate"`
+	ValidateCertificates bool     `json:"validate_certificates"`
+	KerberosRealm        *int     `json:"kerberos_realm"`
+	KerberosPrincipal    string   `json:"kerberos_principal"`
+	Schema               string   `json:"schema"`
+	Timeout              int      `json:"timeout"`
+	DNSTimeout           int      `json:"dns_timeout"`
+	HasSambaSchema       bool     `json:"has_samba_schema"`
+	AuxiliaryParameters  string   `json:"auxiliary_parameters"`
+	Enable               bool     `json:"enable"`
+	JobID                *int     `json:"job_id,omitempty"`
+}
+
+func resourceTrueNASLDAP() *schema.Resource {
+	return &schema.Resource{
+		Description:   "LDAP directory service configuration. This is a singleton, destroying the resource disables LDAP.",
+		CreateContext: resourceTrueNASLDAPCreate,
+		ReadContext:   resourceTrueNASLDAPRead,
+		UpdateContext: resourceTrueNASLDAPUpdate,
+		DeleteContext: resourceTrueNASLDAPDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"hostname": &schema.Schema{
+				Description: "LDAP server hostnames or IP addresses, tried in order",
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"basedn": &schema.Schema{
+				Description: "Top level of the LDAP directory tree, eg. `dc=example,dc=com`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"binddn": &schema.Schema{
+				Description: "Distinguished name used to bind to LDAP server",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"bindpw": &schema.Schema{
+				Description: "Password of `binddn`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"anonbind": &schema.Schema{
+				Description: "Use anonymous bind",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"ssl": &schema.Schema{
+				Description:  "Connection encryption: `OFF`, `ON` (LDAPS) or `START_TLS`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "OFF",
+				ValidateFunc: validation.StringInSlice([]string{"OFF", "ON", "START_TLS"}, false),
+			},
+			"certificate": &schema.Schema{
+				Description: "Certificate ID used for client certificate authentication",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"validate_certificates": &schema.Schema{
+				Description: "Verify LDAP server certificate",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"kerberos_realm": &schema.Schema{
+				Description: "Kerberos realm ID, see `truenas_kerberos_realm`",
+				Type:        schema.TypeInt,
+				Optional:    true,
+			},
+			"kerberos_principal": &schema.Schema{
+				Description: "Kerberos principal from a keytab used for authentication instead of bind credentials",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"schema": &schema.Schema{
+				Description:  "LDAP schema: `RFC2307` or `RFC2307BIS`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "RFC2307",
+				ValidateFunc: validation.StringInSlice([]string{"RFC2307", "RFC2307BIS"}, false),
+			},
+			"timeout": &schema.Schema{
+				Description: "Seconds before LDAP connection attempt times out",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+			},
+			"dns_timeout": &schema.Schema{
+				Description: "Seconds before DNS query times out",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+			},
+			"has_samba_schema": &schema.Schema{
+				Description: "LDAP server has Samba schema, enables SMB authentication for LDAP users",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"auxiliary_parameters": &schema.Schema{
+				Description: "Additional nslcd.conf options",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"idmap_backend": &schema.Schema{
+				Description:  "Idmap backend used for LDAP users and groups: " + strings.Join(idmapBackends, ", "),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(idmapBackends, false),
+			},
+			"enable": &schema.Schema{
+				Description: "Enable LDAP",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"state": &schema.Schema{
+				Description: "LDAP service state reported by TrueNAS, eg. `HEALTHY`, `FAULTED`, `DISABLED`",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASLDAPRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var cfg ldapConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/ldap", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting ldap config: %s", err)
+	}
+
+	if err := d.Set("hostname", flattenStringList(cfg.Hostname)); err != nil {
+		return diag.Errorf("error setting hostname: %s", err)
+	}
+
+	d.Set("basedn", cfg.Basedn)
+	d.Set("binddn", cfg.Binddn)
+	d.Set("anonbind", cfg.Anonbind)
+	d.Set("ssl", cfg.SSL)
+	d.Set("validate_certificates", cfg.ValidateCertificates)
+	d.Set("kerberos_principal", cfg.KerberosPrincipal)
+	d.Set("schema", cfg.Schema)
+	d.Set("timeout", cfg.Timeout)
+	d.Set("dns_timeout", cfg.DNSTimeout)
+	d.Set("has_samba_schema", cfg.HasSambaSchema)
+	d.Set("auxiliary_parameters", cfg.AuxiliaryParameters)
+	d.Set("enable", cfg.Enable)
+
+	if cfg.Certificate != nil {
+		d.Set("certificate", *cfg.Certificate)
+	} else {
+		d.Set("certificate", nil)
+	}
+
+	if cfg.KerberosRealm != nil {
+		d.Set("kerberos_realm", *cfg.KerberosRealm)
+	} else {
+		d.Set("kerberos_realm", nil)
+	}
+
+	idmap, err := getIdmapDomain(ctx, c, idmapLDAP)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("idmap_backend", idmap.IdmapBackend)
+
+	states, err := getDirectoryServicesState(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("state", states["ldap"])
+
+	return diags
+}
+
+func resourceTrueNASLDAPCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	id, err := updateLDAP(ctx, c, d, d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.Itoa(id))
+
+	log.Printf("[INFO] TrueNAS ldap (%s) configured", d.Id())
+
+	return resourceTrueNASLDAPRead(ctx, d, m)
+}
+
+func resourceTrueNASLDAPUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := updateLDAP(ctx, c, d, d.Timeout(schema.TimeoutUpdate))
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceTrueNASLDAPRead(ctx, d, m)
+}
+
+func resourceTrueNASLDAPDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Disabling TrueNAS ldap")
+
+	var res ldapConfig
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/ldap", map[string]interface{}{"enable": false}, &res)
+
+	if err != nil {
+		return diag.Errorf("error disabling ldap: %s", err)
+	}
+
+	if res.JobID != nil {
+		if _, err := waitForJob(ctx, c, *res.JobID, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("error disabling ldap: %s", err)
+		}
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// updateLDAP saves configuration and waits until LDAP is started, returns config ID
+func updateLDAP(ctx context.Context, c *api.APIClient, d *schema.ResourceData, timeout time.Duration) (int, error) {
+	if d.HasChange("idmap_backend") {
+		if backend, ok := d.GetOk("idmap_backend"); ok {
+			if err := updateIdmapBackend(ctx, c, idmapLDAP, backend.(string)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	input := expandLDAP(d)
+
+	log.Printf("[DEBUG] Updating TrueNAS ldap: %s", input.Basedn)
+
+	var res ldapConfig
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/ldap", input, &res)
+
+	if err != nil {
+		return 0, fmt.Errorf("error updating ldap: %s", err)
+	}
+
+	if res.JobID != nil {
+		log.Printf("[DEBUG] Waiting for ldap job %d", *res.JobID)
+
+		if _, err := waitForJob(ctx, c, *res.JobID, timeout); err != nil {
+			return 0, fmt.Errorf("error starting ldap: %s", err)
+		}
+	}
+
+	return res.ID, nil
+}
+
+func expandLDAP(d *schema.ResourceData) ldapConfig {
+	cfg := ldapConfig{
+		Hostname:             expandStrings(d.Get("hostname").([]interface{})),
+		Basedn:               d.Get("basedn").(string),
+		Binddn:               d.Get("binddn").(string),
+		Bindpw:               d.Get("bindpw").(string),
+		Anonbind:             d.Get("anonbind").(bool),
+		SSL:                  d.Get("ssl").(string),
+		ValidateCertificates: d.Get("validate_certificates").(bool),
+		KerberosPrincipal:    d.Get("kerberos_principal").(string),
+		Schema:               d.Get("schema").(string),
+		Timeout:              d.Get("timeout").(int),
+		DNSTimeout:           d.Get("dns_timeout").(int),
+		HasSambaSchema:       d.Get("has_samba_schema").(bool),
+		AuxiliaryParameters:  d.Get("auxiliary_parameters").(string),
+		Enable:               d.Get("enable").(bool),
+	}
+
+	if cert, ok := d.GetOk("certificate"); ok {
+		v := cert.(int)
+		cfg.Certificate = &v
+	}
+
+	if realm, ok := d.GetOk("kerberos_realm"); ok {
+		r := realm.(int)
+		cfg.KerberosRealm = &r
+	}
+
+	return cfg
+}
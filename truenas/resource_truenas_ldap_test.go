@@ -0,0 +1,53 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandLDAP(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASLDAP().Schema, map[string]interface{}{
+		"hostname": []interface{}{"ldap1.example.com", "ldap2.example.com"},
+		"basedn":   "dc=example,dc=com",
+	})
+
+	cfg := expandLDAP(d)
+
+	// server order matters, first one is tried first
+	assert.Equal(t, []string{"ldap1.example.com", "ldap2.example.com"}, cfg.Hostname)
+	assert.Equal(t, "OFF", cfg.SSL)
+	assert.Equal(t, "RFC2307", cfg.Schema)
+	assert.Equal(t, 10, cfg.Timeout)
+	assert.True(t, cfg.ValidateCertificates)
+	assert.True(t, cfg.Enable)
+	assert.Nil(t, cfg.Certificate)
+	assert.Nil(t, cfg.KerberosRealm)
+
+	// existing password is kept when bindpw is not set
+	body, err := json.Marshal(cfg)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(body), `"bindpw"`)
+	assert.Contains(t, string(body), `"certificate":null`)
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASLDAP().Schema, map[string]interface{}{
+		"hostname":           []interface{}{"ldap.example.com"},
+		"basedn":             "dc=example,dc=com",
+		"binddn":             "cn=nas,dc=example,dc=com",
+		"bindpw":             "secret",
+		"ssl":                "START_TLS",
+		"certificate":        3,
+		"kerberos_realm":     2,
+		"kerberos_principal": "nas@EXAMPLE.COM",
+	})
+
+	cfg = expandLDAP(d)
+
+	assert.Equal(t, "secret", cfg.Bindpw)
+	assert.Equal(t, "START_TLS", cfg.SSL)
+	assert.Equal(t, 3, *cfg.Certificate)
+	assert.Equal(t, 2, *cfg.KerberosRealm)
+	assert.Equal(t, "nas@EXAMPLE.COM", cfg.KerberosPrincipal)
+}
@@ -0,0 +1,355 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type mailOAuth struct {
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type mailConfig struct {
+	ID             int        `json:"id,omitempty"`
+	Fromemail      string     `json:"fromemail"`
+	Fromname       string     `json:"fromname"`
+	Outgoingserver string     `json:"outgoingserver"`
+	Port           int        `json:"port"`
+	Security       string     `json:"security"`
+	SMTP           bool       `json:"smtp"`
+	User           string     `json:"user"`
+	Pass           string     `json:"pass,omitempty"`
+	OAuth          *mailOAuth `json:"oauth,omitempty"`
+}
+
+type mailMessage struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+}
+
+func resourceTrueNASMailConfig() *schema.Resource {
+	return &schema.Resource{
+		Description: "Outbound email settings used for alerts and mail sent to local users. This is a singleton, " +
+			"destroying the resource only removes it from the state.",
+		CreateContext: resourceTrueNASMailConfigCreate,
+		ReadContext:   resourceTrueNASMailConfigRead,
+		UpdateContext: resourceTrueNASMailConfigUpdate,
+		DeleteContext: resourceTrueNASMailConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"fromemail": &schema.Schema{
+				Description: "Email address used as sender",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"fromname": &schema.Schema{
+				Description: "Name used as sender",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"outgoingserver": &schema.Schema{
+				Description: "Hostname or IP address of SMTP server",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"port": &schema.Schema{
+				Description:  "SMTP port",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      25,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"security": &schema.Schema{
+				Description:  "Encryption type: `PLAIN` (no encryption), `SSL` (implicit TLS) or `TLS` (STARTTLS)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "PLAIN",
+				ValidateFunc: validation.StringInSlice([]string{"PLAIN", "SSL", "TLS"}, false),
+			},
+			"smtp_auth": &schema.Schema{
+				Description: "Enable SMTP authentication",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"user": &schema.Schema{
+				Description: "SMTP username",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"pass": &schema.Schema{
+				Description: "SMTP password",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"oauth": &schema.Schema{
+				Description: "OAuth settings for Gmail or Outlook, used instead of SMTP server (SCALE)",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"client_id": &schema.Schema{
+							Description: "OAuth client ID",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"client_secret": &schema.Schema{
+							Description: "OAuth client secret",
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+						},
+						"refresh_token": &schema.Schema{
+							Description: "OAuth refresh token",
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
+			"root_email": &schema.Schema{
+				Description: "Email address of the root user, system mail for root is forwarded to it",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"send_test_email": &schema.Schema{
+				Description: "Send a test email with the new settings before they are saved, apply fails if it can't be delivered",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceTrueNASMailConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var cfg mailConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/mail", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting mail config: %s", err)
+	}
+
+	d.Set("fromemail", cfg.Fromemail)
+	d.Set("fromname", cfg.Fromname)
+	d.Set("outgoingserver", cfg.Outgoingserver)
+	d.Set("port", cfg.Port)
+	d.Set("security", cfg.Security)
+	d.Set("smtp_auth", cfg.SMTP)
+	d.Set("user", cfg.User)
+
+	// secrets are kept from configuration, TrueNAS versions differ in whether they are returned
+	if cfg.OAuth != nil && cfg.OAuth.ClientID != "" {
+		oauth := map[string]interface{}{
+			"client_id":     cfg.OAuth.ClientID,
+			"client_secret": d.Get("oauth.0.client_secret"),
+			"refresh_token": d.Get("oauth.0.refresh_token"),
+		}
+
+		if err := d.Set("oauth", []interface{}{oauth}); err != nil {
+			return diag.Errorf("error setting oauth: %s", err)
+		}
+	} else {
+		d.Set("oauth", nil)
+	}
+
+	root, err := getRootUser(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("root_email", root.GetEmail())
+
+	return diags
+}
+
+func resourceTrueNASMailConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	diags := resourceTrueNASMailConfigApply(ctx, d, m, d.Timeout(schema.TimeoutCreate))
+
+	if diags.HasError() {
+		return diags
+	}
+
+	var cfg mailConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/mail", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting mail config: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(cfg.ID))
+
+	return append(diags, resourceTrueNASMailConfigRead(ctx, d, m)...)
+}
+
+func resourceTrueNASMailConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	diags := resourceTrueNASMailConfigApply(ctx, d, m, d.Timeout(schema.TimeoutUpdate))
+
+	if diags.HasError() {
+		return diags
+	}
+
+	return append(diags, resourceTrueNASMailConfigRead(ctx, d, m)...)
+}
+
+func resourceTrueNASMailConfigApply(ctx context.Context, d *schema.ResourceData, m interface{}, timeout time.Duration) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandMailConfig(d)
+
+	// on update only test when settings were actually changed
+	if d.Get("send_test_email").(bool) && (d.IsNewResource() || d.HasChangeExcept("send_test_email")) {
+		if err := sendTestEmail(ctx, c, input, timeout); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	log.Printf("[DEBUG] Updating TrueNAS mail config")
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/mail", input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating mail config: %s", err)
+	}
+
+	if email, ok := d.GetOk("root_email"); ok && d.HasChange("root_email") {
+		root, err := getRootUser(ctx, c)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		params := api.NewUpdateUserParams()
+		params.SetEmail(email.(string))
+
+		_, _, err = c.UserApi.UpdateUser(ctx, root.Id).UpdateUserParams(*params).Execute()
+
+		if err != nil {
+			var body []byte
+			if apiErr, ok := err.(*api.GenericOpenAPIError); ok {
+				body = apiErr.Body()
+			}
+			return diag.Errorf("error updating root email: %s\n%s", err, body)
+		}
+	}
+
+	return nil
+}
+
+func resourceTrueNASMailConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	log.Printf("[DEBUG] Removing TrueNAS mail config from state, settings are left unchanged")
+
+	d.SetId("")
+
+	return diags
+}
+
+// sendTestEmail sends a test message to root using given settings instead of the saved ones
+func sendTestEmail(ctx context.Context, c *api.APIClient, cfg mailConfig, timeout time.Duration) error {
+	input := map[string]interface{}{
+		"mail_message": mailMessage{
+			Subject: "TrueNAS test email",
+			Text:    "This is a test message sent while applying TrueNAS mail configuration.",
+		},
+		"config": cfg,
+	}
+
+	var jobID int
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/mail/send", input, &jobID)
+
+	if err != nil {
+		return fmt.Errorf("error sending test email: %s", err)
+	}
+
+	res, err := waitForJob(ctx, c, jobID, timeout)
+
+	if err != nil {
+		return fmt.Errorf("error sending test email: %s", err)
+	}
+
+	var sent bool
+
+	if err := json.Unmarshal(res, &sent); err == nil && !sent {
+		return fmt.Errorf("test email was not sent, check TrueNAS mail log for details")
+	}
+
+	return nil
+}
+
+func getRootUser(ctx context.Context, c *api.APIClient) (*api.User, error) {
+	users, _, err := c.UserApi.ListUsers(ctx).Execute()
+
+	if err != nil {
+		var body []byte
+		if apiErr, ok := err.(*api.GenericOpenAPIError); ok {
+			body = apiErr.Body()
+		}
+		return nil, fmt.Errorf("error getting users: %s\n%s", err, body)
+	}
+
+	for _, u := range users {
+		if u.Username == "root" {
+			return &u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("root user not found")
+}
+
+func expandMailConfig(d *schema.ResourceData) mailConfig {
+	cfg := mailConfig{
+		Fromemail:      d.Get("fromemail").(string),
+		Fromname:       d.Get("fromname").(string),
+		Outgoingserver: d.Get("outgoingserver").(string),
+		Port:           d.Get("port").(int),
+		Security:       d.Get("security").(string),
+		SMTP:           d.Get("smtp_auth").(bool),
+		User:           d.Get("user").(string),
+		Pass:           d.Get("pass").(string),
+	}
+
+	if v, ok := d.GetOk("oauth"); ok {
+		oauth := v.([]interface{})[0].(map[string]interface{})
+
+		cfg.OAuth = &mailOAuth{
+			ClientID:     oauth["client_id"].(string),
+			ClientSecret: oauth["client_secret"].(string),
+			RefreshToken: oauth["refresh_token"].(string),
+		}
+	}
+
+	return cfg
+}
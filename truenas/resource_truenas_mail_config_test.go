@@ -0,0 +1,72 @@
+package truenas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccResourceTruenasMailConfig_basic(t *testing.T) {
+	resourceName := "truenas_mail_config.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasMailConfigConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "fromemail", "nas@example.com"),
+					resource.TestCheckResourceAttr(resourceName, "fromname", "TrueNAS"),
+					resource.TestCheckResourceAttr(resourceName, "outgoingserver", "smtp.example.com"),
+					resource.TestCheckResourceAttr(resourceName, "port", "587"),
+					resource.TestCheckResourceAttr(resourceName, "security", "TLS"),
+					resource.TestCheckResourceAttr(resourceName, "smtp_auth", "true"),
+					resource.TestCheckResourceAttr(resourceName, "user", "nas"),
+					resource.TestCheckResourceAttr(resourceName, "root_email", "root@example.com"),
+				),
+			},
+		},
+	})
+}
+
+func Test_expandMailConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASMailConfig().Schema, map[string]interface{}{
+		"fromemail":      "nas@example.com",
+		"outgoingserver": "smtp.gmail.com",
+		"oauth": []interface{}{
+			map[string]interface{}{
+				"client_id":     "id",
+				"client_secret": "secret",
+				"refresh_token": "token",
+			},
+		},
+	})
+
+	cfg := expandMailConfig(d)
+
+	assert.Equal(t, 25, cfg.Port)
+	assert.Equal(t, "PLAIN", cfg.Security)
+	assert.Equal(t, &mailOAuth{ClientID: "id", ClientSecret: "secret", RefreshToken: "token"}, cfg.OAuth)
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASMailConfig().Schema, map[string]interface{}{
+		"fromemail": "nas@example.com",
+	})
+
+	assert.Nil(t, expandMailConfig(d).OAuth)
+}
+
+const testAccCheckResourceTruenasMailConfigConfig = `
+	resource "truenas_mail_config" "test" {
+		fromemail = "nas@example.com"
+		fromname = "TrueNAS"
+		outgoingserver = "smtp.example.com"
+		port = 587
+		security = "TLS"
+		smtp_auth = true
+		user = "nas"
+		pass = "secret"
+		root_email = "root@example.com"
+	}
+`
@@ -0,0 +1,186 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type ntpServer struct {
+	ID      int    `json:"id,omitempty"`
+	Address string `json:"address"`
+	Burst   bool   `json:"burst"`
+	Iburst  bool   `json:"iburst"`
+	Prefer  bool   `json:"prefer"`
+	Minpoll int    `json:"minpoll"`
+	Maxpoll int    `json:"maxpoll"`
+	Force   bool   `json:"force,omitempty"`
+}
+
+func resourceTrueNASNTPServer() *schema.Resource {
+	return &schema.Resource{
+		Description:   "NTP servers TrueNAS synchronizes its clock with",
+		CreateContext: resourceTrueNASNTPServerCreate,
+		ReadContext:   resourceTrueNASNTPServerRead,
+		UpdateContext: resourceTrueNASNTPServerUpdate,
+		DeleteContext: resourceTrueNASNTPServerDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"ntp_server_id": &schema.Schema{
+				Description: "NTP server ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"address": &schema.Schema{
+				Description: "Hostname or IP address of NTP server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"burst": &schema.Schema{
+				Description: "Send a burst of eight packets when server is reachable",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"iburst": &schema.Schema{
+				Description: "Send a burst of eight packets when server is unreachable, speeds up initial synchronization",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"prefer": &schema.Schema{
+				Description: "Prefer this server over others",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"minpoll": &schema.Schema{
+				Description:  "Minimum polling interval, as power of 2 in seconds",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      6,
+				ValidateFunc: validation.IntBetween(4, 17),
+			},
+			"maxpoll": &schema.Schema{
+				Description:  "Maximum polling interval, as power of 2 in seconds",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(4, 17),
+			},
+			"force": &schema.Schema{
+				Description: "Save the server even if it is not reachable",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+		},
+	}
+}
+
+func resourceTrueNASNTPServerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var server ntpServer
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/system/ntpserver/id/%d", id), nil, &server)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting ntp server: %s", err)
+	}
+
+	d.Set("ntp_server_id", server.ID)
+	d.Set("address", server.Address)
+	d.Set("burst", server.Burst)
+	d.Set("iburst", server.Iburst)
+	d.Set("prefer", server.Prefer)
+	d.Set("minpoll", server.Minpoll)
+	d.Set("maxpoll", server.Maxpoll)
+
+	return diags
+}
+
+func resourceTrueNASNTPServerCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandNTPServer(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS ntp server: %s", input.Address)
+
+	var server ntpServer
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/system/ntpserver", input, &server)
+
+	if err != nil {
+		return diag.Errorf("error creating ntp server: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(server.ID))
+
+	log.Printf("[INFO] TrueNAS ntp server (%s) created", d.Id())
+
+	return resourceTrueNASNTPServerRead(ctx, d, m)
+}
+
+func resourceTrueNASNTPServerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/system/ntpserver/id/%s", d.Id()), expandNTPServer(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating ntp server: %s", err)
+	}
+
+	return resourceTrueNASNTPServerRead(ctx, d, m)
+}
+
+func resourceTrueNASNTPServerDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS ntp server: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/system/ntpserver/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting ntp server: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS ntp server (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandNTPServer(d *schema.ResourceData) ntpServer {
+	return ntpServer{
+		Address: d.Get("address").(string),
+		Burst:   d.Get("burst").(bool),
+		Iburst:  d.Get("iburst").(bool),
+		Prefer:  d.Get("prefer").(bool),
+		Minpoll: d.Get("minpoll").(int),
+		Maxpoll: d.Get("maxpoll").(int),
+		Force:   d.Get("force").(bool),
+	}
+}
@@ -0,0 +1,45 @@
+package truenas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"testing"
+)
+
+func TestAccResourceTruenasNTPServer_basic(t *testing.T) {
+	resourceName := "truenas_ntp_server.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasNTPServerConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "address", "192.0.2.123"),
+					resource.TestCheckResourceAttr(resourceName, "iburst", "true"),
+					resource.TestCheckResourceAttr(resourceName, "prefer", "true"),
+					resource.TestCheckResourceAttr(resourceName, "minpoll", "4"),
+					resource.TestCheckResourceAttr(resourceName, "maxpoll", "8"),
+					resource.TestCheckResourceAttrSet(resourceName, "ntp_server_id"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force"},
+			},
+		},
+	})
+}
+
+// test server is not reachable, force skips connectivity check
+const testAccCheckResourceTruenasNTPServerConfig = `
+	resource "truenas_ntp_server" "test" {
+		address = "192.0.2.123"
+		prefer = true
+		minpoll = 4
+		maxpoll = 8
+		force = true
+	}
+`
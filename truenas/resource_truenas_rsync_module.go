@@ -0,0 +1,233 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type rsyncModule struct {
+	ID         int      `json:"id,omitempty"`
+	Name       string   `json:"name"`
+	Comment    string   `json:"comment"`
+	Path       string   `json:"path"`
+	Mode       string   `json:"mode"`
+	Maxconn    int      `json:"maxconn"`
+	User       string   `json:"user"`
+	Group      string   `json:"group"`
+	Hostsallow []string `json:"hostsallow"`
+	Hostsdeny  []string `json:"hostsdeny"`
+	Auxiliary  string   `json:"auxiliary"`
+	Enabled    bool     `json:"enabled"`
+}
+
+func resourceTrueNASRsyncModule() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Rsync modules are shared by the rsyncd service running on TrueNAS, remote systems can push data to or pull data from them.",
+		CreateContext: resourceTrueNASRsyncModuleCreate,
+		ReadContext:   resourceTrueNASRsyncModuleRead,
+		UpdateContext: resourceTrueNASRsyncModuleUpdate,
+		DeleteContext: resourceTrueNASRsyncModuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"rsync_module_id": &schema.Schema{
+				Description: "Rsync module ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"name": &schema.Schema{
+				Description:  "Module name, must match the name remote systems use to connect",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringDoesNotContainAny("/]"),
+			},
+			"comment": &schema.Schema{
+				Description: "Any notes about this module",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"path": &schema.Schema{
+				Description: "Path to the shared directory",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"mode": &schema.Schema{
+				Description:  "Access mode: `RO` (read only), `RW` (read and write) or `WO` (write only)",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "RO",
+				ValidateFunc: validation.StringInSlice([]string{"RO", "RW", "WO"}, false),
+			},
+			"maxconn": &schema.Schema{
+				Description:  "Maximum number of simultaneous connections, `0` is unlimited",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"user": &schema.Schema{
+				Description: "User to run the file transfers as",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "nobody",
+			},
+			"group": &schema.Schema{
+				Description: "Group to run the file transfers as",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "nogroup",
+			},
+			"hostsallow": &schema.Schema{
+				Description: "Authorized hosts (IP/hostname/network)",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"hostsdeny": &schema.Schema{
+				Description: "Disallowed hosts (IP/hostname/network)",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"auxiliary": &schema.Schema{
+				Description: "Auxiliary rsyncd.conf module parameters",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"enabled": &schema.Schema{
+				Description: "Enable this module",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASRsyncModuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var mod rsyncModule
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/rsyncmod/id/%d", id), nil, &mod)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting rsync module: %s", err)
+	}
+
+	d.Set("rsync_module_id", mod.ID)
+	d.Set("name", mod.Name)
+	d.Set("comment", mod.Comment)
+	d.Set("path", mod.Path)
+	d.Set("mode", mod.Mode)
+	d.Set("maxconn", mod.Maxconn)
+	d.Set("user", mod.User)
+	d.Set("group", mod.Group)
+	d.Set("auxiliary", mod.Auxiliary)
+	d.Set("enabled", mod.Enabled)
+
+	if err := d.Set("hostsallow", flattenStringList(mod.Hostsallow)); err != nil {
+		return diag.Errorf("error setting hostsallow: %s", err)
+	}
+
+	if err := d.Set("hostsdeny", flattenStringList(mod.Hostsdeny)); err != nil {
+		return diag.Errorf("error setting hostsdeny: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASRsyncModuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandRsyncModule(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS rsync module: %+v", input)
+
+	var mod rsyncModule
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/rsyncmod", input, &mod)
+
+	if err != nil {
+		return diag.Errorf("error creating rsync module: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(mod.ID))
+
+	log.Printf("[INFO] TrueNAS rsync module (%s) created", d.Id())
+
+	return resourceTrueNASRsyncModuleRead(ctx, d, m)
+}
+
+func resourceTrueNASRsyncModuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandRsyncModule(d)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/rsyncmod/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating rsync module: %s", err)
+	}
+
+	return resourceTrueNASRsyncModuleRead(ctx, d, m)
+}
+
+func resourceTrueNASRsyncModuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS rsync module: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/rsyncmod/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting rsync module: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS rsync module (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandRsyncModule(d *schema.ResourceData) rsyncModule {
+	return rsyncModule{
+		Name:       d.Get("name").(string),
+		Comment:    d.Get("comment").(string),
+		Path:       d.Get("path").(string),
+		Mode:       d.Get("mode").(string),
+		Maxconn:    d.Get("maxconn").(int),
+		User:       d.Get("user").(string),
+		Group:      d.Get("group").(string),
+		Hostsallow: expandStrings(d.Get("hostsallow").(*schema.Set).List()),
+		Hostsdeny:  expandStrings(d.Get("hostsdeny").(*schema.Set).List()),
+		Auxiliary:  d.Get("auxiliary").(string),
+		Enabled:    d.Get("enabled").(bool),
+	}
+}
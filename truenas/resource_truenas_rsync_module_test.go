@@ -0,0 +1,49 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandRsyncModule(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASRsyncModule().Schema, map[string]interface{}{
+		"name": "configs",
+		"path": "/mnt/Tank/configs",
+	})
+
+	mod := expandRsyncModule(d)
+
+	assert.Equal(t, "configs", mod.Name)
+	assert.Equal(t, "RO", mod.Mode)
+	assert.Equal(t, "nobody", mod.User)
+	assert.Equal(t, "nogroup", mod.Group)
+	assert.True(t, mod.Enabled)
+
+	// unset host lists are sent as empty arrays, not null
+	body, err := json.Marshal(mod)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"hostsallow":[]`)
+	assert.Contains(t, string(body), `"hostsdeny":[]`)
+	assert.NotContains(t, string(body), `"id"`)
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASRsyncModule().Schema, map[string]interface{}{
+		"name":       "backups",
+		"path":       "/mnt/Tank/backups",
+		"mode":       "WO",
+		"maxconn":    4,
+		"hostsallow": []interface{}{"10.0.0.0/24"},
+		"hostsdeny":  []interface{}{"ALL"},
+		"enabled":    false,
+	})
+
+	mod = expandRsyncModule(d)
+
+	assert.Equal(t, "WO", mod.Mode)
+	assert.Equal(t, 4, mod.Maxconn)
+	assert.Equal(t, []string{"10.0.0.0/24"}, mod.Hostsallow)
+	assert.Equal(t, []string{"ALL"}, mod.Hostsdeny)
+	assert.False(t, mod.Enabled)
+}
@@ -0,0 +1,353 @@
+package truenas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type rsyncTask struct {
+	ID            int                  `json:"id,omitempty"`
+	Path          string               `json:"path"`
+	User          string               `json:"user"`
+	Mode          string               `json:"mode"`
+	Remotehost    string               `json:"remotehost"`
+	Remoteport    int                  `json:"remoteport"`
+	Remotemodule  string               `json:"remotemodule"`
+	Remotepath    string               `json:"remotepath"`
+	ValidateRpath bool                 `json:"validate_rpath"`
+	Direction     string               `json:"direction"`
+	Desc          string               `json:"desc"`
+	Schedule      *api.CronJobSchedule `json:"schedule,omitempty"`
+	Recursive     bool                 `json:"recursive"`
+	Times         bool                 `json:"times"`
+	Compress      bool                 `json:"compress"`
+	Archive       bool                 `json:"archive"`
+	Delete        bool                 `json:"delete"`
+	Quiet         bool                 `json:"quiet"`
+	Preserveperm  bool                 `json:"preserveperm"`
+	Preserveattr  bool                 `json:"preserveattr"`
+	Delayupdates  bool                 `json:"delayupdates"`
+	Extra         []string             `json:"extra"`
+	Enabled       bool                 `json:"enabled"`
+}
+
+func resourceTrueNASRsyncTask() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Rsync tasks copy data to or from remote systems on a schedule, either using an rsync module on the remote host or over SSH.",
+		CreateContext: resourceTrueNASRsyncTaskCreate,
+		ReadContext:   resourceTrueNASRsyncTaskRead,
+		UpdateContext: resourceTrueNASRsyncTaskUpdate,
+		DeleteContext: resourceTrueNASRsyncTaskDelete,
+		CustomizeDiff: resourceTrueNASRsyncTaskCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"rsync_task_id": &schema.Schema{
+				Description: "Rsync task ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"path": &schema.Schema{
+				Description: "Local path to sync, eg. `/mnt/Tank/configs`",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"user": &schema.Schema{
+				Description: "Account that is used to run the task",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"mode": &schema.Schema{
+				Description:  "`MODULE` connects to rsync module on the remote host, `SSH` uses rsync over SSH",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "MODULE",
+				ValidateFunc: validation.StringInSlice([]string{"MODULE", "SSH"}, false),
+			},
+			"remotehost": &schema.Schema{
+				Description: "IP address or hostname of the remote system, `username@host` is accepted in `SSH` mode",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"remoteport": &schema.Schema{
+				Description:  "SSH port of the remote system",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      22,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"remotemodule": &schema.Schema{
+				Description: "Name of the rsync module on the remote host, required in `MODULE` mode",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"remotepath": &schema.Schema{
+				Description: "Path on the remote system, required in `SSH` mode",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"validate_rpath": &schema.Schema{
+				Description: "Verify that remote path exists",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"direction": &schema.Schema{
+				Description:  "`PUSH` copies local path to the remote system, `PULL` copies from the remote system to local path",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "PUSH",
+				ValidateFunc: validation.StringInSlice([]string{"PUSH", "PULL"}, false),
+			},
+			"description": &schema.Schema{
+				Description: "Rsync task description",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"schedule": resourceScheduleSchema("Rsync task schedule"),
+			"recursive": &schema.Schema{
+				Description: "Include all subdirectories",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"times": &schema.Schema{
+				Description: "Preserve modification times of files",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"compress": &schema.Schema{
+				Description: "Reduce the size of data to transmit",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"archive": &schema.Schema{
+				Description: "Equivalent to `-rlptgoD`, run recursively, preserving symlinks, permissions, modification times, group, and special files",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"delete": &schema.Schema{
+				Description: "Delete files in the destination directory that do not exist in the source directory",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"quiet": &schema.Schema{
+				Description: "Suppress informational messages from the remote server",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"preserveperm": &schema.Schema{
+				Description: "Preserve original file permissions",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"preserveattr": &schema.Schema{
+				Description: "Preserve extended attributes",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"delayupdates": &schema.Schema{
+				Description: "Save the temporary file from each updated file to a holding directory, at the end of the transfer all transferred files are renamed into place",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"extra": &schema.Schema{
+				Description: "Additional rsync arguments",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"enabled": &schema.Schema{
+				Description: "`true` if rsync task is enabled",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASRsyncTaskRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var task rsyncTask
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/rsynctask/id/%d", id), nil, &task)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting rsync task: %s", err)
+	}
+
+	d.Set("rsync_task_id", task.ID)
+	d.Set("path", task.Path)
+	d.Set("user", task.User)
+	d.Set("mode", task.Mode)
+	d.Set("remotehost", task.Remotehost)
+	d.Set("remoteport", task.Remoteport)
+	d.Set("remotemodule", task.Remotemodule)
+	d.Set("remotepath", task.Remotepath)
+	d.Set("validate_rpath", task.ValidateRpath)
+	d.Set("direction", task.Direction)
+	d.Set("description", task.Desc)
+	d.Set("recursive", task.Recursive)
+	d.Set("times", task.Times)
+	d.Set("compress", task.Compress)
+	d.Set("archive", task.Archive)
+	d.Set("delete", task.Delete)
+	d.Set("quiet", task.Quiet)
+	d.Set("preserveperm", task.Preserveperm)
+	d.Set("preserveattr", task.Preserveattr)
+	d.Set("delayupdates", task.Delayupdates)
+	d.Set("enabled", task.Enabled)
+
+	if err := d.Set("extra", flattenStringList(task.Extra)); err != nil {
+		return diag.Errorf("error setting extra: %s", err)
+	}
+
+	if task.Schedule != nil {
+		if err := d.Set("schedule", flattenSchedule(*task.Schedule)); err != nil {
+			return diag.Errorf("error setting schedule: %s", err)
+		}
+	}
+
+	return diags
+}
+
+func resourceTrueNASRsyncTaskCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandRsyncTask(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS rsync task: %+v", input)
+
+	var task rsyncTask
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/rsynctask", input, &task)
+
+	if err != nil {
+		return diag.Errorf("error creating rsync task: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(task.ID))
+
+	log.Printf("[INFO] TrueNAS rsync task (%s) created", d.Id())
+
+	return resourceTrueNASRsyncTaskRead(ctx, d, m)
+}
+
+func resourceTrueNASRsyncTaskUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandRsyncTask(d)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/rsynctask/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating rsync task: %s", err)
+	}
+
+	return resourceTrueNASRsyncTaskRead(ctx, d, m)
+}
+
+func resourceTrueNASRsyncTaskDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS rsync task: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/rsynctask/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting rsync task: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS rsync task (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+// resourceTrueNASRsyncTaskCustomizeDiff makes sure the remote attribute matching mode is set
+func resourceTrueNASRsyncTaskCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("mode") || !d.NewValueKnown("remotemodule") || !d.NewValueKnown("remotepath") {
+		return nil
+	}
+
+	return validateRsyncTaskMode(d.Get("mode").(string), d.Get("remotemodule").(string), d.Get("remotepath").(string))
+}
+
+func validateRsyncTaskMode(mode string, remoteModule string, remotePath string) error {
+	if mode == "MODULE" && remoteModule == "" {
+		return errors.New("remotemodule is required when mode is MODULE")
+	}
+
+	if mode == "SSH" && remotePath == "" {
+		return errors.New("remotepath is required when mode is SSH")
+	}
+
+	return nil
+}
+
+func expandRsyncTask(d *schema.ResourceData) rsyncTask {
+	task := rsyncTask{
+		Path:          d.Get("path").(string),
+		User:          d.Get("user").(string),
+		Mode:          d.Get("mode").(string),
+		Remotehost:    d.Get("remotehost").(string),
+		Remoteport:    d.Get("remoteport").(int),
+		Remotemodule:  d.Get("remotemodule").(string),
+		Remotepath:    d.Get("remotepath").(string),
+		ValidateRpath: d.Get("validate_rpath").(bool),
+		Direction:     d.Get("direction").(string),
+		Desc:          d.Get("description").(string),
+		Recursive:     d.Get("recursive").(bool),
+		Times:         d.Get("times").(bool),
+		Compress:      d.Get("compress").(bool),
+		Archive:       d.Get("archive").(bool),
+		Delete:        d.Get("delete").(bool),
+		Quiet:         d.Get("quiet").(bool),
+		Preserveperm:  d.Get("preserveperm").(bool),
+		Preserveattr:  d.Get("preserveattr").(bool),
+		Delayupdates:  d.Get("delayupdates").(bool),
+		Extra:         expandStrings(d.Get("extra").([]interface{})),
+		Enabled:       d.Get("enabled").(bool),
+	}
+
+	if schedule, ok := d.GetOk("schedule"); ok {
+		task.Schedule = expandJobSchedule(schedule.([]interface{}))
+	}
+
+	return task
+}
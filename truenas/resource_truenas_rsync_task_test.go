@@ -0,0 +1,111 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccResourceTruenasRsyncTask_basic(t *testing.T) {
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	name := fmt.Sprintf("%s-%s", testResourcePrefix, suffix)
+	resourceName := "truenas_rsync_task.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasRsyncTaskConfig(testPoolName, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "user", "root"),
+					resource.TestCheckResourceAttr(resourceName, "mode", "MODULE"),
+					resource.TestCheckResourceAttr(resourceName, "remotehost", "127.0.0.1"),
+					resource.TestCheckResourceAttr(resourceName, "remotemodule", name),
+					resource.TestCheckResourceAttr(resourceName, "direction", "PULL"),
+					resource.TestCheckResourceAttr(resourceName, "description", "tf rsync task"),
+					resource.TestCheckResourceAttr(resourceName, "archive", "true"),
+					resource.TestCheckResourceAttr(resourceName, "extra.0", "--exclude=*.tmp"),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.hour", "3"),
+					resource.TestCheckResourceAttr("truenas_rsync_module.test", "name", name),
+					resource.TestCheckResourceAttr("truenas_rsync_module.test", "mode", "RW"),
+					resource.TestCheckResourceAttr("truenas_rsync_module.test", "hostsallow.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func Test_validateRsyncTaskMode(t *testing.T) {
+	assert.NoError(t, validateRsyncTaskMode("MODULE", "configs", ""))
+	assert.Error(t, validateRsyncTaskMode("MODULE", "", "/etc"))
+	assert.NoError(t, validateRsyncTaskMode("SSH", "", "/etc"))
+	assert.Error(t, validateRsyncTaskMode("SSH", "configs", ""))
+}
+
+func Test_resourceTrueNASRsyncTaskCustomizeDiff(t *testing.T) {
+	r := resourceTrueNASRsyncTask()
+	raw := map[string]interface{}{
+		"path":       "/mnt/Tank/configs",
+		"user":       "root",
+		"remotehost": "legacy.example.com",
+		"mode":       "SSH",
+		"remotepath": "/etc",
+	}
+
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+
+	assert.NoError(t, err)
+
+	raw["mode"] = "MODULE"
+
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+
+	assert.EqualError(t, err, "remotemodule is required when mode is MODULE")
+
+	// module name of a resource created in the same run is not known yet, SDK marks unknown values with this UUID
+	raw["remotemodule"] = "74D93920-ED26-11E3-AC10-0800200C9A66"
+
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+
+	assert.NoError(t, err)
+}
+
+func testAccCheckResourceTruenasRsyncTaskConfig(pool string, name string) string {
+	return fmt.Sprintf(`
+	resource "truenas_dataset" "test" {
+		name = "%s"
+		pool = "%s"
+	}
+
+	resource "truenas_rsync_module" "test" {
+		name = "%s"
+		path = truenas_dataset.test.mount_point
+		mode = "RW"
+		user = "root"
+		group = "wheel"
+		hostsallow = ["127.0.0.1"]
+	}
+
+	resource "truenas_rsync_task" "test" {
+		path = truenas_dataset.test.mount_point
+		user = "root"
+		mode = "MODULE"
+		remotehost = "127.0.0.1"
+		remotemodule = truenas_rsync_module.test.name
+		direction = "PULL"
+		description = "tf rsync task"
+		archive = true
+		extra = ["--exclude=*.tmp"]
+		enabled = false
+		schedule {
+			minute = "0"
+			hour = "3"
+		}
+	}
+	`, name, pool, name)
+}
@@ -0,0 +1,172 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type scrubTask struct {
+	ID          int                  `json:"id,omitempty"`
+	Pool        int                  `json:"pool"`
+	Threshold   int                  `json:"threshold"`
+	Description string               `json:"description"`
+	Schedule    *api.CronJobSchedule `json:"schedule,omitempty"`
+	Enabled     bool                 `json:"enabled"`
+}
+
+func resourceTrueNASScrubTask() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Scheduled pool scrubs, scrub reads all data in the pool and repairs any checksum errors it finds",
+		CreateContext: resourceTrueNASScrubTaskCreate,
+		ReadContext:   resourceTrueNASScrubTaskRead,
+		UpdateContext: resourceTrueNASScrubTaskUpdate,
+		DeleteContext: resourceTrueNASScrubTaskDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"scrub_task_id": &schema.Schema{
+				Description: "Scrub task ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"pool": &schema.Schema{
+				Description: "Pool ID, see `truenas_pool_ids`",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+			"threshold": &schema.Schema{
+				Description:  "Days since last scrub before the scheduled scrub runs, scheduled runs are skipped until then",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      35,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"description": &schema.Schema{
+				Description: "Optional scrub task description",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"schedule": resourceScheduleSchema("Scrub task schedule"),
+			"enabled": &schema.Schema{
+				Description: "`true` if scrub task is enabled",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASScrubTaskRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var task scrubTask
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/pool/scrub/id/%d", id), nil, &task)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting scrub task: %s", err)
+	}
+
+	d.Set("scrub_task_id", task.ID)
+	d.Set("pool", task.Pool)
+	d.Set("threshold", task.Threshold)
+	d.Set("description", task.Description)
+	d.Set("enabled", task.Enabled)
+
+	if task.Schedule != nil {
+		if err := d.Set("schedule", flattenSchedule(*task.Schedule)); err != nil {
+			return diag.Errorf("error setting schedule: %s", err)
+		}
+	}
+
+	return diags
+}
+
+func resourceTrueNASScrubTaskCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandScrubTask(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS scrub task: %+v", input)
+
+	var task scrubTask
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/pool/scrub", input, &task)
+
+	if err != nil {
+		return diag.Errorf("error creating scrub task: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(task.ID))
+
+	log.Printf("[INFO] TrueNAS scrub task (%s) created", d.Id())
+
+	return resourceTrueNASScrubTaskRead(ctx, d, m)
+}
+
+func resourceTrueNASScrubTaskUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/pool/scrub/id/%s", d.Id()), expandScrubTask(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating scrub task: %s", err)
+	}
+
+	return resourceTrueNASScrubTaskRead(ctx, d, m)
+}
+
+func resourceTrueNASScrubTaskDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS scrub task: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/pool/scrub/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting scrub task: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS scrub task (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandScrubTask(d *schema.ResourceData) scrubTask {
+	task := scrubTask{
+		Pool:        d.Get("pool").(int),
+		Threshold:   d.Get("threshold").(int),
+		Description: d.Get("description").(string),
+		Enabled:     d.Get("enabled").(bool),
+	}
+
+	if schedule, ok := d.GetOk("schedule"); ok {
+		task.Schedule = expandJobSchedule(schedule.([]interface{}))
+	}
+
+	return task
+}
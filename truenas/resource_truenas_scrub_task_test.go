@@ -0,0 +1,47 @@
+package truenas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"testing"
+)
+
+func TestAccResourceTruenasScrubTask_basic(t *testing.T) {
+	resourceName := "truenas_scrub_task.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasScrubTaskConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "pool"),
+					resource.TestCheckResourceAttr(resourceName, "threshold", "28"),
+					resource.TestCheckResourceAttr(resourceName, "description", "tf scrub task"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.minute", "30"),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.hour", "1"),
+					resource.TestCheckResourceAttrSet(resourceName, "scrub_task_id"),
+				),
+			},
+		},
+	})
+}
+
+// TrueNAS allows a single scrub task per pool, test task is disabled
+// and the existing one (if any) has to be removed first
+const testAccCheckResourceTruenasScrubTaskConfig = `
+	data "truenas_pool_ids" "all" {}
+
+	resource "truenas_scrub_task" "test" {
+		pool = tolist(data.truenas_pool_ids.all.ids)[0]
+		threshold = 28
+		description = "tf scrub task"
+		enabled = false
+		schedule {
+			minute = "30"
+			hour = "1"
+			dow = "7"
+		}
+	}
+`
@@ -2,12 +2,16 @@ package truenas
 
 import (
 	"context"
+	"fmt"
 	api "github.com/dariusbakunas/truenas-go-sdk"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"log"
+	"net/http"
+	"path"
 	"strconv"
+	"strings"
 )
 
 func resourceTrueNASShareNFS() *schema.Resource {
@@ -17,6 +21,7 @@ func resourceTrueNASShareNFS() *schema.Resource {
 		ReadContext:   resourceTrueNASShareNFSRead,
 		UpdateContext: resourceTrueNASShareNFSUpdate,
 		DeleteContext: resourceTrueNASShareNFSDelete,
+		CustomizeDiff: resourceTrueNASShareNFSCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -94,7 +99,7 @@ func resourceTrueNASShareNFS() *schema.Resource {
 				Default:     true,
 			},
 			"paths": &schema.Schema{
-				Description: "Sharing paths",
+				Description: "Sharing paths, directories under `/mnt/<pool>`. Paths known at plan time must exist and can not be on a zvol.",
 				Type:        schema.TypeSet,
 				Required:    true,
 				Elem: &schema.Schema{
@@ -324,3 +329,129 @@ func expandShareNFS(d *schema.ResourceData) api.CreateShareNFSParams {
 
 	return share
 }
+
+// resourceTrueNASShareNFSCustomizeDiff validates known paths, paths referencing datasets
+// that are not created yet are checked by TrueNAS on apply
+func resourceTrueNASShareNFSCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("paths") {
+		return nil
+	}
+
+	config := d.GetRawConfig()
+
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	pathsConfig := config.GetAttr("paths")
+
+	if pathsConfig.IsNull() || !pathsConfig.IsKnown() {
+		return nil
+	}
+
+	paths := []string{}
+
+	for it := pathsConfig.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+
+		if v.IsKnown() && !v.IsNull() {
+			paths = append(paths, v.AsString())
+		}
+	}
+
+	return validateSharePaths(ctx, m.(*api.APIClient), "paths", paths)
+}
+
+// validateSharePaths makes sure paths are under /mnt/<pool>, are not on a zvol and exist
+func validateSharePaths(ctx context.Context, c *api.APIClient, attr string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	pools, _, err := c.PoolApi.ListPools(ctx).Execute()
+
+	if err != nil {
+		return fmt.Errorf("error listing pools: %s", err)
+	}
+
+	poolNames := []string{}
+
+	for _, p := range pools {
+		poolNames = append(poolNames, p.Name)
+	}
+
+	// only zvol IDs are needed, full dataset listing with all properties is expensive
+	var zvols []struct {
+		ID string `json:"id"`
+	}
+
+	if _, err := callAPI(ctx, c, http.MethodGet, "/pool/dataset?type=VOLUME", nil, &zvols); err != nil {
+		return fmt.Errorf("error listing zvols: %s", err)
+	}
+
+	volumes := map[string]bool{}
+
+	for _, zvol := range zvols {
+		volumes[zvol.ID] = true
+	}
+
+	errs := []string{}
+
+	for _, p := range paths {
+		if err := checkSharePath(p, poolNames, volumes); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %q %s", attr, p, err))
+			continue
+		}
+
+		var stat struct {
+			Type string `json:"type"`
+		}
+
+		resp, err := callAPI(ctx, c, http.MethodPost, "/filesystem/stat", p, &stat)
+
+		if err != nil {
+			// TrueNAS responds with validation error if path does not exist
+			if resp != nil && (resp.StatusCode == 404 || resp.StatusCode == 422) {
+				errs = append(errs, fmt.Sprintf("%s: %q does not exist, reference mount_point of a truenas_dataset if it is created in the same run", attr, p))
+				continue
+			}
+
+			return fmt.Errorf("error checking %s %q: %s", attr, p, err)
+		}
+
+		if stat.Type != "" && stat.Type != "DIRECTORY" {
+			errs = append(errs, fmt.Sprintf("%s: %q is not a directory", attr, p))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid share %s:\n%s", attr, strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
+// checkSharePath validates share path against pool names and zvol IDs
+func checkSharePath(p string, pools []string, volumes map[string]bool) error {
+	p = path.Clean(p)
+
+	if !strings.HasPrefix(p, "/mnt/") {
+		return fmt.Errorf("must be under /mnt/<pool>")
+	}
+
+	id := strings.TrimPrefix(p, "/mnt/")
+	pool := strings.Split(id, "/")[0]
+
+	if !containsString(pools, pool) {
+		return fmt.Errorf("is not on a pool, %s is not one of: %s", pool, strings.Join(pools, ", "))
+	}
+
+	// path on zvol or any of its parents
+	for parts := strings.Split(id, "/"); len(parts) > 1; parts = parts[:len(parts)-1] {
+		if volumes[strings.Join(parts, "/")] {
+			return fmt.Errorf("is on zvol %s, zvols can not be shared by path", strings.Join(parts, "/"))
+		}
+	}
+
+	return nil
+}
@@ -8,8 +8,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -241,3 +245,58 @@ func testAccCheckResourceTruenasShareNFSDatasetDestroy(s *terraform.State) error
 
 	return nil
 }
+
+func Test_checkSharePath(t *testing.T) {
+	pools := []string{"Tank", "Backup"}
+	volumes := map[string]bool{"Tank/vm": true}
+
+	assert.NoError(t, checkSharePath("/mnt/Tank/media", pools, volumes))
+	assert.NoError(t, checkSharePath("/mnt/Backup/", pools, volumes))
+	assert.EqualError(t, checkSharePath("/home/media", pools, volumes), "must be under /mnt/<pool>")
+	assert.EqualError(t, checkSharePath("/mnt", pools, volumes), "must be under /mnt/<pool>")
+	assert.EqualError(t, checkSharePath("/mnt/Tnak/media", pools, volumes), "is not on a pool, Tnak is not one of: Tank, Backup")
+	assert.EqualError(t, checkSharePath("/mnt/Tank/vm/disk", pools, volumes), "is on zvol Tank/vm, zvols can not be shared by path")
+}
+
+func Test_validateSharePaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimLeft(strings.TrimPrefix(r.URL.Path, "/api/v2.0"), "/") {
+		case "pool":
+			fmt.Fprint(w, `[{"id": 1, "name": "Tank"}]`)
+		case "pool/dataset":
+			if r.URL.Query().Get("type") != "VOLUME" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `[{"id": "Tank/vm", "type": "VOLUME"}]`)
+		case "filesystem/stat":
+			body, _ := ioutil.ReadAll(r.Body)
+
+			switch string(body) {
+			case `"/mnt/Tank/media"`:
+				fmt.Fprint(w, `{"type": "DIRECTORY"}`)
+			case `"/mnt/Tank/file.txt"`:
+				fmt.Fprint(w, `{"type": "FILE"}`)
+			default:
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				fmt.Fprint(w, `{"message": "Path does not exist"}`)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL + "/api/v2.0/")
+
+	assert.NoError(t, validateSharePaths(context.Background(), c, "paths", []string{"/mnt/Tank/media"}))
+
+	err := validateSharePaths(context.Background(), c, "paths", []string{"/mnt/Tank/media", "/mnt/Tank/missing", "/mnt/Tank/vm", "/mnt/Tank/file.txt"})
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `paths: "/mnt/Tank/missing" does not exist`)
+		assert.Contains(t, err.Error(), `paths: "/mnt/Tank/vm" is on zvol Tank/vm`)
+		assert.Contains(t, err.Error(), `paths: "/mnt/Tank/file.txt" is not a directory`)
+		assert.NotContains(t, err.Error(), `"/mnt/Tank/media"`)
+	}
+}
@@ -19,6 +19,7 @@ func resourceTrueNASShareSMB() *schema.Resource {
 		ReadContext:   resourceTrueNASShareSMBRead,
 		UpdateContext: resourceTrueNASShareSMBUpdate,
 		DeleteContext: resourceTrueNASShareSMBDelete,
+		CustomizeDiff: resourceTrueNASShareSMBCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -29,7 +30,7 @@ func resourceTrueNASShareSMB() *schema.Resource {
 				Computed:    true,
 			},
 			"path": &schema.Schema{
-				Description: "Path to shared directory",
+				Description: "Path to shared directory under `/mnt/<pool>`. Path known at plan time must exist and can not be on a zvol.",
 				Type:        schema.TypeString,
 				Required:    true,
 			},
@@ -316,6 +317,15 @@ func resourceTrueNASShareSMBUpdate(ctx context.Context, d *schema.ResourceData,
 	return resourceTrueNASShareSMBRead(ctx, d, m)
 }
 
+// resourceTrueNASShareSMBCustomizeDiff validates path once it is known
+func resourceTrueNASShareSMBCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("path") || !d.NewValueKnown("path") {
+		return nil
+	}
+
+	return validateSharePaths(ctx, m.(*api.APIClient), "path", []string{d.Get("path").(string)})
+}
+
 func expandShareSMB(d *schema.ResourceData) (api.CreateShareSMBParams, error) {
 	share := api.CreateShareSMBParams{
 		Path: d.Get("path").(string),
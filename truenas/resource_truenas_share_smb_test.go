@@ -8,6 +8,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 )
@@ -89,6 +92,69 @@ func Test_lockedSMBProfileParamIsRejected(t *testing.T) {
 	}
 }
 
+func Test_resourceTrueNASShareSMBCustomizeDiff(t *testing.T) {
+	stats := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pool":
+			fmt.Fprint(w, `[{"id": 1, "name": "Tank"}]`)
+		case "/pool/dataset":
+			fmt.Fprint(w, `[{"id": "Tank/vm", "type": "VOLUME"}]`)
+		case "/filesystem/stat":
+			stats++
+			body, _ := ioutil.ReadAll(r.Body)
+
+			if string(body) == `"/mnt/Tank/media"` {
+				fmt.Fprint(w, `{"type": "DIRECTORY"}`)
+				return
+			}
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"message": "Path does not exist"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestAPIClient(server.URL)
+	r := resourceTrueNASShareSMB()
+
+	diff := func(state *terraform.InstanceState, path string) error {
+		_, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{"path": path}), c)
+		return err
+	}
+
+	assert.NoError(t, diff(nil, "/mnt/Tank/media"))
+
+	err := diff(nil, "/mnt/Tank/missing")
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `path: "/mnt/Tank/missing" does not exist`)
+	}
+
+	err = diff(nil, "/mnt/Tank/vm/disk")
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `path: "/mnt/Tank/vm/disk" is on zvol Tank/vm`)
+	}
+
+	stats = 0
+
+	// path of a dataset created in the same run is not known yet, SDK marks unknown values with this UUID
+	assert.NoError(t, diff(nil, "74D93920-ED26-11E3-AC10-0800200C9A66"))
+
+	// unchanged path is not checked again
+	state := &terraform.InstanceState{
+		ID:         "1",
+		Attributes: map[string]string{"id": "1", "path": "/mnt/Tank/gone"},
+	}
+
+	assert.NoError(t, diff(state, "/mnt/Tank/gone"))
+	assert.Equal(t, 0, stats)
+}
+
 func testAccCheckResourceTruenasShareSMBConfig(pool string, datasetName string) string {
 	return fmt.Sprintf(`
 	resource "truenas_dataset" "test" {
@@ -0,0 +1,143 @@
+package truenas
+
+import (
+	"context"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type smartConfig struct {
+	ID            int    `json:"id,omitempty"`
+	Interval      int    `json:"interval"`
+	Powermode     string `json:"powermode"`
+	Difference    int    `json:"difference"`
+	Informational int    `json:"informational"`
+	Critical      int    `json:"critical"`
+}
+
+func resourceTrueNASSMARTConfig() *schema.Resource {
+	return &schema.Resource{
+		Description:   "S.M.A.R.T. service configuration. This is a singleton, destroying the resource only removes it from the state.",
+		CreateContext: resourceTrueNASSMARTConfigCreate,
+		ReadContext:   resourceTrueNASSMARTConfigRead,
+		UpdateContext: resourceTrueNASSMARTConfigUpdate,
+		DeleteContext: resourceTrueNASSMARTConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"interval": &schema.Schema{
+				Description:  "Minutes between disk checks",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"powermode": &schema.Schema{
+				Description:  "Skip disk checks in this power mode to let disks sleep: `NEVER`, `SLEEP`, `STANDBY` or `IDLE`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "NEVER",
+				ValidateFunc: validation.StringInSlice([]string{"NEVER", "SLEEP", "STANDBY", "IDLE"}, false),
+			},
+			"difference": &schema.Schema{
+				Description:  "Report temperature changes of this many degrees Celsius since last check, `0` disables reporting",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"informational": &schema.Schema{
+				Description:  "Report informational message when temperature reaches this many degrees Celsius, `0` disables reporting",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"critical": &schema.Schema{
+				Description:  "Report critical message when temperature reaches this many degrees Celsius, `0` disables reporting",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+	}
+}
+
+func resourceTrueNASSMARTConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var cfg smartConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/smart", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting smart config: %s", err)
+	}
+
+	d.Set("interval", cfg.Interval)
+	d.Set("powermode", cfg.Powermode)
+	d.Set("difference", cfg.Difference)
+	d.Set("informational", cfg.Informational)
+	d.Set("critical", cfg.Critical)
+
+	return diags
+}
+
+func resourceTrueNASSMARTConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Updating TrueNAS smart config")
+
+	var cfg smartConfig
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/smart", expandSMARTConfig(d), &cfg)
+
+	if err != nil {
+		return diag.Errorf("error updating smart config: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(cfg.ID))
+
+	return resourceTrueNASSMARTConfigRead(ctx, d, m)
+}
+
+func resourceTrueNASSMARTConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/smart", expandSMARTConfig(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating smart config: %s", err)
+	}
+
+	return resourceTrueNASSMARTConfigRead(ctx, d, m)
+}
+
+func resourceTrueNASSMARTConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	log.Printf("[DEBUG] Removing TrueNAS smart config from state, settings are left unchanged")
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandSMARTConfig(d *schema.ResourceData) smartConfig {
+	return smartConfig{
+		Interval:      d.Get("interval").(int),
+		Powermode:     d.Get("powermode").(string),
+		Difference:    d.Get("difference").(int),
+		Informational: d.Get("informational").(int),
+		Critical:      d.Get("critical").(int),
+	}
+}
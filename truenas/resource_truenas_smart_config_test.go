@@ -0,0 +1,27 @@
+package truenas
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandSMARTConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASSMARTConfig().Schema, map[string]interface{}{})
+
+	cfg := expandSMARTConfig(d)
+
+	assert.Equal(t, smartConfig{Interval: 30, Powermode: "NEVER"}, cfg)
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASSMARTConfig().Schema, map[string]interface{}{
+		"interval":      60,
+		"powermode":     "STANDBY",
+		"difference":    5,
+		"informational": 45,
+		"critical":      55,
+	})
+
+	cfg = expandSMARTConfig(d)
+
+	assert.Equal(t, smartConfig{Interval: 60, Powermode: "STANDBY", Difference: 5, Informational: 45, Critical: 55}, cfg)
+}
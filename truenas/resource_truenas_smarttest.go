@@ -0,0 +1,178 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type smartTest struct {
+	ID       int                  `json:"id,omitempty"`
+	Desc     string               `json:"desc"`
+	AllDisks bool                 `json:"all_disks"`
+	Disks    []string             `json:"disks"`
+	Type     string               `json:"type"`
+	Schedule *api.CronJobSchedule `json:"schedule,omitempty"`
+}
+
+func resourceTrueNASSMARTTest() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Scheduled S.M.A.R.T. tests of disks",
+		CreateContext: resourceTrueNASSMARTTestCreate,
+		ReadContext:   resourceTrueNASSMARTTestRead,
+		UpdateContext: resourceTrueNASSMARTTestUpdate,
+		DeleteContext: resourceTrueNASSMARTTestDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"smart_test_id": &schema.Schema{
+				Description: "S.M.A.R.T. test ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"description": &schema.Schema{
+				Description: "Optional test description",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"all_disks": &schema.Schema{
+				Description:  "Run test on all disks",
+				Type:         schema.TypeBool,
+				Optional:     true,
+				Default:      false,
+				ExactlyOneOf: []string{"all_disks", "disks"},
+			},
+			"disks": &schema.Schema{
+				Description: "Disk identifiers to test, eg. `{serial}WD-WCC4N0123456`",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"type": &schema.Schema{
+				Description:  "Test type: `LONG`, `SHORT`, `CONVEYANCE` or `OFFLINE`",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"LONG", "SHORT", "CONVEYANCE", "OFFLINE"}, false),
+			},
+			"schedule": resourceHourlyScheduleSchema("S.M.A.R.T. test schedule, tests start at the beginning of the hour"),
+		},
+	}
+}
+
+func resourceTrueNASSMARTTestRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var test smartTest
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/smart/test/id/%d", id), nil, &test)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting smart test: %s", err)
+	}
+
+	d.Set("smart_test_id", test.ID)
+	d.Set("description", test.Desc)
+	d.Set("all_disks", test.AllDisks)
+	d.Set("type", test.Type)
+
+	if err := d.Set("disks", flattenStringList(test.Disks)); err != nil {
+		return diag.Errorf("error setting disks: %s", err)
+	}
+
+	if test.Schedule != nil {
+		if err := d.Set("schedule", flattenSchedule(*test.Schedule)); err != nil {
+			return diag.Errorf("error setting schedule: %s", err)
+		}
+	}
+
+	return diags
+}
+
+func resourceTrueNASSMARTTestCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := expandSMARTTest(d)
+
+	log.Printf("[DEBUG] Creating TrueNAS smart test: %+v", input)
+
+	var test smartTest
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/smart/test", input, &test)
+
+	if err != nil {
+		return diag.Errorf("error creating smart test: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(test.ID))
+
+	log.Printf("[INFO] TrueNAS smart test (%s) created", d.Id())
+
+	return resourceTrueNASSMARTTestRead(ctx, d, m)
+}
+
+func resourceTrueNASSMARTTestUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/smart/test/id/%s", d.Id()), expandSMARTTest(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating smart test: %s", err)
+	}
+
+	return resourceTrueNASSMARTTestRead(ctx, d, m)
+}
+
+func resourceTrueNASSMARTTestDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS smart test: %s", d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/smart/test/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting smart test: %s", err)
+	}
+
+	log.Printf("[INFO] TrueNAS smart test (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func expandSMARTTest(d *schema.ResourceData) smartTest {
+	test := smartTest{
+		Desc:     d.Get("description").(string),
+		AllDisks: d.Get("all_disks").(bool),
+		Disks:    expandStrings(d.Get("disks").(*schema.Set).List()),
+		Type:     d.Get("type").(string),
+	}
+
+	if schedule, ok := d.GetOk("schedule"); ok {
+		test.Schedule = expandJobSchedule(schedule.([]interface{}))
+	}
+
+	return test
+}
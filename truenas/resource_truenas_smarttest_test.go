@@ -0,0 +1,68 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccResourceTruenasSMARTTest_basic(t *testing.T) {
+	resourceName := "truenas_smart_test.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasSMARTTestConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "all_disks", "true"),
+					resource.TestCheckResourceAttr(resourceName, "type", "SHORT"),
+					resource.TestCheckResourceAttr(resourceName, "description", "tf smart test"),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.hour", "4"),
+					resource.TestCheckResourceAttr(resourceName, "schedule.0.dow", "7"),
+					resource.TestCheckResourceAttrSet(resourceName, "smart_test_id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_expandSMARTTestSchedule(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASSMARTTest().Schema, map[string]interface{}{
+		"all_disks": true,
+		"type":      "LONG",
+		"schedule": []interface{}{
+			map[string]interface{}{
+				"hour": "2",
+				"dom":  "1",
+			},
+		},
+	})
+
+	test := expandSMARTTest(d)
+
+	b, err := json.Marshal(test.Schedule)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"hour": "2", "dom": "1", "month": "*", "dow": "*"}`, string(b))
+}
+
+const testAccCheckResourceTruenasSMARTTestConfig = `
+	resource "truenas_smart_test" "test" {
+		all_disks = true
+		type = "SHORT"
+		description = "tf smart test"
+		schedule {
+			hour = "4"
+			dow = "7"
+		}
+	}
+`
@@ -0,0 +1,208 @@
+package truenas
+
+import (
+	"context"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+var sftpLogLevels = []string{"", "QUIET", "FATAL", "ERROR", "INFO", "VERBOSE", "DEBUG", "DEBUG2", "DEBUG3"}
+
+var sftpLogFacilities = []string{"", "DAEMON", "USER", "AUTH", "LOCAL0", "LOCAL1", "LOCAL2", "LOCAL3", "LOCAL4", "LOCAL5", "LOCAL6", "LOCAL7"}
+
+type sshConfig struct {
+	ID              int      `json:"id,omitempty"`
+	Bindiface       []string `json:"bindiface"`
+	Tcpport         int      `json:"tcpport"`
+	Rootlogin       bool     `json:"rootlogin"`
+	Passwordauth    bool     `json:"passwordauth"`
+	Kerberosauth    bool     `json:"kerberosauth"`
+	Tcpfwd          bool     `json:"tcpfwd"`
+	Compression     bool     `json:"compression"`
+	SftpLogLevel    string   `json:"sftp_log_level"`
+	SftpLogFacility string   `json:"sftp_log_facility"`
+	WeakCiphers     []string `json:"weak_ciphers"`
+	Options         string   `json:"options"`
+}
+
+func resourceTrueNASSSHConfig() *schema.Resource {
+	return &schema.Resource{
+		Description: "SSH service configuration. This is a singleton, destroying the resource only removes it from the state, " +
+			"use `truenas_service` to start the service.",
+		CreateContext: resourceTrueNASSSHConfigCreate,
+		ReadContext:   resourceTrueNASSSHConfigRead,
+		UpdateContext: resourceTrueNASSSHConfigUpdate,
+		DeleteContext: resourceTrueNASSSHConfigDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"bindiface": &schema.Schema{
+				Description: "Interfaces SSH listens on, all interfaces are used if empty",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"tcpport": &schema.Schema{
+				Description:  "Port SSH listens on",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      22,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"rootlogin": &schema.Schema{
+				Description: "Allow root to log in with password",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"passwordauth": &schema.Schema{
+				Description: "Allow password authentication",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"kerberosauth": &schema.Schema{
+				Description: "Allow Kerberos authentication",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"tcpfwd": &schema.Schema{
+				Description: "Allow TCP port forwarding",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"compression": &schema.Schema{
+				Description: "Compress data before it is sent",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"weak_ciphers": &schema.Schema{
+				Description: "Allow weak ciphers: `AES128-CBC`, `NONE`",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"AES128-CBC", "NONE"}, false),
+				},
+			},
+			"sftp_log_level": &schema.Schema{
+				Description:  "SFTP log level: `QUIET`, `FATAL`, `ERROR`, `INFO`, `VERBOSE`, `DEBUG`, `DEBUG2`, `DEBUG3`, logging is disabled if empty",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(sftpLogLevels, false),
+			},
+			"sftp_log_facility": &schema.Schema{
+				Description:  "SFTP syslog facility, eg. `AUTH` or `LOCAL0`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(sftpLogFacilities, false),
+			},
+			"options": &schema.Schema{
+				Description: "Additional sshd_config options",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASSSHConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	var cfg sshConfig
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/ssh", nil, &cfg)
+
+	if err != nil {
+		return diag.Errorf("error getting ssh config: %s", err)
+	}
+
+	d.Set("tcpport", cfg.Tcpport)
+	d.Set("rootlogin", cfg.Rootlogin)
+	d.Set("passwordauth", cfg.Passwordauth)
+	d.Set("kerberosauth", cfg.Kerberosauth)
+	d.Set("tcpfwd", cfg.Tcpfwd)
+	d.Set("compression", cfg.Compression)
+	d.Set("sftp_log_level", cfg.SftpLogLevel)
+	d.Set("sftp_log_facility", cfg.SftpLogFacility)
+	d.Set("options", cfg.Options)
+
+	if err := d.Set("bindiface", flattenStringList(cfg.Bindiface)); err != nil {
+		return diag.Errorf("error setting bindiface: %s", err)
+	}
+
+	if err := d.Set("weak_ciphers", flattenStringList(cfg.WeakCiphers)); err != nil {
+		return diag.Errorf("error setting weak_ciphers: %s", err)
+	}
+
+	return diags
+}
+
+func resourceTrueNASSSHConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Updating TrueNAS ssh config")
+
+	var cfg sshConfig
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/ssh", expandSSHConfig(d), &cfg)
+
+	if err != nil {
+		return diag.Errorf("error updating ssh config: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(cfg.ID))
+
+	return resourceTrueNASSSHConfigRead(ctx, d, m)
+}
+
+func resourceTrueNASSSHConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/ssh", expandSSHConfig(d), nil)
+
+	if err != nil {
+		return diag.Errorf("error updating ssh config: %s", err)
+	}
+
+	return resourceTrueNASSSHConfigRead(ctx, d, m)
+}
+
+func resourceTrueNASSSHConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	log.Printf("[DEBUG] Removing TrueNAS ssh config from state, settings are left unchanged")
+
+	d.SetId("")
+
+	return diags
+}
+
+func expandSSHConfig(d *schema.ResourceData) sshConfig {
+	return sshConfig{
+		Bindiface:       expandStrings(d.Get("bindiface").(*schema.Set).List()),
+		Tcpport:         d.Get("tcpport").(int),
+		Rootlogin:       d.Get("rootlogin").(bool),
+		Passwordauth:    d.Get("passwordauth").(bool),
+		Kerberosauth:    d.Get("kerberosauth").(bool),
+		Tcpfwd:          d.Get("tcpfwd").(bool),
+		Compression:     d.Get("compression").(bool),
+		SftpLogLevel:    d.Get("sftp_log_level").(string),
+		SftpLogFacility: d.Get("sftp_log_facility").(string),
+		WeakCiphers:     expandStrings(d.Get("weak_ciphers").(*schema.Set).List()),
+		Options:         d.Get("options").(string),
+	}
+}
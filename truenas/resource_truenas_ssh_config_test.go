@@ -0,0 +1,44 @@
+package truenas
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandSSHConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASSSHConfig().Schema, map[string]interface{}{})
+
+	cfg := expandSSHConfig(d)
+
+	assert.Equal(t, 22, cfg.Tcpport)
+	assert.False(t, cfg.Rootlogin)
+	assert.False(t, cfg.Passwordauth)
+	assert.Equal(t, "", cfg.SftpLogLevel)
+
+	// all interfaces and no weak ciphers are sent as empty lists
+	body, err := json.Marshal(cfg)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"bindiface":[]`)
+	assert.Contains(t, string(body), `"weak_ciphers":[]`)
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASSSHConfig().Schema, map[string]interface{}{
+		"bindiface":      []interface{}{"eno1"},
+		"tcpport":        2222,
+		"passwordauth":   true,
+		"weak_ciphers":   []interface{}{"AES128-CBC"},
+		"sftp_log_level": "VERBOSE",
+		"options":        "ClientAliveInterval 60",
+	})
+
+	cfg = expandSSHConfig(d)
+
+	assert.Equal(t, []string{"eno1"}, cfg.Bindiface)
+	assert.Equal(t, 2222, cfg.Tcpport)
+	assert.True(t, cfg.Passwordauth)
+	assert.Equal(t, []string{"AES128-CBC"}, cfg.WeakCiphers)
+	assert.Equal(t, "VERBOSE", cfg.SftpLogLevel)
+	assert.Equal(t, "ClientAliveInterval 60", cfg.Options)
+}
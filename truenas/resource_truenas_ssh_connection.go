@@ -0,0 +1,328 @@
+package truenas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type sshHostKeyScan struct {
+	Host           string `json:"host"`
+	Port           int    `json:"port"`
+	ConnectTimeout int    `json:"connect_timeout"`
+}
+
+type sshSemiAutomaticSetup struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	Token          string `json:"token"`
+	PrivateKey     int    `json:"private_key"`
+	Cipher         string `json:"cipher"`
+	ConnectTimeout int    `json:"connect_timeout"`
+}
+
+func resourceTrueNASSSHConnection() *schema.Resource {
+	return &schema.Resource{
+		Description: "SSH connections stored in TrueNAS keychain, used by replication and rsync tasks. " +
+			"Connection can be set up manually or semi-automatically against a remote TrueNAS using an auth token.",
+		CreateContext: resourceTrueNASSSHConnectionCreate,
+		ReadContext:   resourceTrueNASSSHConnectionRead,
+		UpdateContext: resourceTrueNASSSHConnectionUpdate,
+		DeleteContext: resourceTrueNASSSHConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"connection_id": &schema.Schema{
+				Description: "SSH connection ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"name": &schema.Schema{
+				Description: "SSH connection name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"setup_type": &schema.Schema{
+				Description:      "`MANUAL` requires `host`, `SEMI-AUTOMATIC` registers public key on remote TrueNAS using `url` and `token`",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "MANUAL",
+				ForceNew:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"MANUAL", "SEMI-AUTOMATIC"}, false),
+				DiffSuppressFunc: suppressCreateOnlyAttributeOnImport,
+			},
+			"url": &schema.Schema{
+				Description:      "Remote TrueNAS URL, eg. `https://nas2.example.com`, used by `SEMI-AUTOMATIC` setup",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCreateOnlyAttributeOnImport,
+			},
+			"token": &schema.Schema{
+				Description:      "Auth token generated on remote TrueNAS, used by `SEMI-AUTOMATIC` setup",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressCreateOnlyAttributeOnImport,
+			},
+			"host": &schema.Schema{
+				Description: "Remote hostname or IP address, required for `MANUAL` setup",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"port": &schema.Schema{
+				Description:  "Remote SSH port, defaults to `22`",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"username": &schema.Schema{
+				Description: "Remote username, defaults to `root`",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"private_key": &schema.Schema{
+				Description: "SSH keypair ID, see `truenas_ssh_keypair`",
+				Type:        schema.TypeInt,
+				Required:    true,
+			},
+			"remote_host_key": &schema.Schema{
+				Description: "Remote host key, scanned from remote host if not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"cipher": &schema.Schema{
+				Description:  "Cipher: `STANDARD`, `FAST` or `DISABLED`",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "STANDARD",
+				ValidateFunc: validation.StringInSlice([]string{"STANDARD", "FAST", "DISABLED"}, false),
+			},
+			"connect_timeout": &schema.Schema{
+				Description: "Seconds before connection attempt times out",
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+			},
+		},
+	}
+}
+
+// suppressCreateOnlyAttributeOnImport hides diff for attributes that are only used
+// when resource is created and can't be read back, so imported resources are not replaced
+func suppressCreateOnlyAttributeOnImport(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != "" && old == ""
+}
+
+func resourceTrueNASSSHConnectionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var cred keychainCredential
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/keychaincredential/id/%d", id), nil, &cred)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting ssh connection: %s", err)
+	}
+
+	d.Set("connection_id", cred.ID)
+	d.Set("name", cred.Name)
+
+	for _, k := range []string{"host", "username", "remote_host_key", "cipher"} {
+		if v, ok := cred.Attributes[k].(string); ok {
+			d.Set(k, v)
+		}
+	}
+
+	for _, k := range []string{"port", "private_key", "connect_timeout"} {
+		if v, ok := cred.Attributes[k].(float64); ok {
+			d.Set(k, int(v))
+		}
+	}
+
+	return diags
+}
+
+func resourceTrueNASSSHConnectionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	var cred keychainCredential
+
+	if d.Get("setup_type").(string) == "SEMI-AUTOMATIC" {
+		input, err := expandSSHSemiAutomaticSetup(d)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("[DEBUG] Setting up TrueNAS ssh connection %s with %s", input.Name, input.URL)
+
+		_, err = callAPI(ctx, c, http.MethodPost, "/keychaincredential/remote_ssh_semiautomatic_setup", input, &cred)
+
+		if err != nil {
+			return diag.Errorf("error setting up ssh connection: %s", err)
+		}
+	} else {
+		input, err := expandSSHConnection(d)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if input.Attributes["remote_host_key"] == "" {
+			key, err := scanSSHHostKey(ctx, c, input.Attributes)
+
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			input.Attributes["remote_host_key"] = key
+		}
+
+		log.Printf("[DEBUG] Creating TrueNAS ssh connection: %s", input.Name)
+
+		_, err = callAPI(ctx, c, http.MethodPost, "/keychaincredential", input, &cred)
+
+		if err != nil {
+			return diag.Errorf("error creating ssh connection: %s", err)
+		}
+	}
+
+	d.SetId(strconv.Itoa(cred.ID))
+
+	log.Printf("[INFO] TrueNAS ssh connection (%s) created", d.Id())
+
+	return resourceTrueNASSSHConnectionRead(ctx, d, m)
+}
+
+func resourceTrueNASSSHConnectionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input, err := expandSSHConnection(d)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// host key has to be scanned again if remote host changed and key is not managed
+	if d.HasChanges("host", "port") && !d.HasChange("remote_host_key") {
+		key, err := scanSSHHostKey(ctx, c, input.Attributes)
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		input.Attributes["remote_host_key"] = key
+	}
+
+	// type can't be changed
+	input.Type = ""
+
+	_, err = callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/keychaincredential/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating ssh connection: %s", err)
+	}
+
+	return resourceTrueNASSSHConnectionRead(ctx, d, m)
+}
+
+func resourceTrueNASSSHConnectionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteKeychainCredential(ctx, d, m, "ssh connection")
+}
+
+func scanSSHHostKey(ctx context.Context, c *api.APIClient, attributes map[string]interface{}) (string, error) {
+	input := sshHostKeyScan{
+		Host:           attributes["host"].(string),
+		Port:           attributes["port"].(int),
+		ConnectTimeout: attributes["connect_timeout"].(int),
+	}
+
+	log.Printf("[DEBUG] Scanning ssh host key of %s:%d", input.Host, input.Port)
+
+	var key string
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/keychaincredential/remote_ssh_host_key_scan", input, &key)
+
+	if err != nil {
+		return "", fmt.Errorf("error scanning ssh host key of %s: %s", input.Host, err)
+	}
+
+	return key, nil
+}
+
+func expandSSHConnection(d *schema.ResourceData) (keychainCredential, error) {
+	host := d.Get("host").(string)
+
+	if host == "" {
+		return keychainCredential{}, errors.New("host is required when setup_type is MANUAL")
+	}
+
+	port := d.Get("port").(int)
+
+	if port == 0 {
+		port = 22
+	}
+
+	username := d.Get("username").(string)
+
+	if username == "" {
+		username = "root"
+	}
+
+	return keychainCredential{
+		Name: d.Get("name").(string),
+		Type: "SSH_CREDENTIALS",
+		Attributes: map[string]interface{}{
+			"host":            host,
+			"port":            port,
+			"username":        username,
+			"private_key":     d.Get("private_key").(int),
+			"remote_host_key": d.Get("remote_host_key").(string),
+			"cipher":          d.Get("cipher").(string),
+			"connect_timeout": d.Get("connect_timeout").(int),
+		},
+	}, nil
+}
+
+func expandSSHSemiAutomaticSetup(d *schema.ResourceData) (sshSemiAutomaticSetup, error) {
+	setup := sshSemiAutomaticSetup{
+		Name:           d.Get("name").(string),
+		URL:            d.Get("url").(string),
+		Token:          d.Get("token").(string),
+		PrivateKey:     d.Get("private_key").(int),
+		Cipher:         d.Get("cipher").(string),
+		ConnectTimeout: d.Get("connect_timeout").(int),
+	}
+
+	if setup.URL == "" || setup.Token == "" {
+		return setup, errors.New("url and token are required when setup_type is SEMI-AUTOMATIC")
+	}
+
+	return setup, nil
+}
@@ -0,0 +1,94 @@
+package truenas
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccResourceTruenasSSHConnection_basic(t *testing.T) {
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	name := fmt.Sprintf("%s-%s", testResourcePrefix, suffix)
+	resourceName := "truenas_ssh_connection.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasSSHConnectionConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("truenas_ssh_keypair.test", "public_key"),
+					resource.TestCheckResourceAttrSet("truenas_ssh_keypair.test", "private_key"),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "host", "127.0.0.1"),
+					resource.TestCheckResourceAttr(resourceName, "port", "22"),
+					resource.TestCheckResourceAttr(resourceName, "username", "root"),
+					resource.TestCheckResourceAttr(resourceName, "cipher", "FAST"),
+					resource.TestCheckResourceAttrPair(resourceName, "private_key", "truenas_ssh_keypair.test", "keypair_id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// create only attributes
+				ImportStateVerifyIgnore: []string{"setup_type"},
+			},
+		},
+	})
+}
+
+func Test_expandSSHConnection(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASSSHConnection().Schema, map[string]interface{}{
+		"name":        "backup",
+		"host":        "backup.example.com",
+		"private_key": 1,
+	})
+
+	cred, err := expandSSHConnection(d)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SSH_CREDENTIALS", cred.Type)
+	assert.Equal(t, 22, cred.Attributes["port"])
+	assert.Equal(t, "root", cred.Attributes["username"])
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASSSHConnection().Schema, map[string]interface{}{
+		"name":        "backup",
+		"private_key": 1,
+	})
+
+	_, err = expandSSHConnection(d)
+
+	assert.Error(t, err)
+
+	d = schema.TestResourceDataRaw(t, resourceTrueNASSSHConnection().Schema, map[string]interface{}{
+		"name":        "backup",
+		"setup_type":  "SEMI-AUTOMATIC",
+		"url":         "https://nas2.example.com",
+		"private_key": 1,
+	})
+
+	_, err = expandSSHSemiAutomaticSetup(d)
+
+	assert.Error(t, err)
+}
+
+func testAccCheckResourceTruenasSSHConnectionConfig(name string) string {
+	return fmt.Sprintf(`
+	resource "truenas_ssh_keypair" "test" {
+		name = "%s"
+	}
+
+	resource "truenas_ssh_connection" "test" {
+		name = "%s"
+		host = "127.0.0.1"
+		private_key = truenas_ssh_keypair.test.keypair_id
+		remote_host_key = "127.0.0.1 ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+		cipher = "FAST"
+	}
+	`, name, name)
+}
@@ -0,0 +1,201 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// keychainCredential is an entry of TrueNAS keychain, used for SSH keypairs and SSH connections
+type keychainCredential struct {
+	ID         int                    `json:"id,omitempty"`
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type,omitempty"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type sshKeyPair struct {
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+}
+
+func resourceTrueNASSSHKeypair() *schema.Resource {
+	return &schema.Resource{
+		Description:   "SSH keypairs stored in TrueNAS keychain, used by SSH connections for replication and rsync tasks",
+		CreateContext: resourceTrueNASSSHKeypairCreate,
+		ReadContext:   resourceTrueNASSSHKeypairRead,
+		UpdateContext: resourceTrueNASSSHKeypairUpdate,
+		DeleteContext: resourceTrueNASSSHKeypairDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"keypair_id": &schema.Schema{
+				Description: "SSH keypair ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"name": &schema.Schema{
+				Description: "SSH keypair name",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"private_key": &schema.Schema{
+				Description: "Private key to import, new keypair is generated by TrueNAS if not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+			},
+			"public_key": &schema.Schema{
+				Description: "Public key, derived from private key if not set",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASSSHKeypairRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var cred keychainCredential
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/keychaincredential/id/%d", id), nil, &cred)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting ssh keypair: %s", err)
+	}
+
+	d.Set("keypair_id", cred.ID)
+	d.Set("name", cred.Name)
+
+	if v, ok := cred.Attributes["private_key"].(string); ok {
+		d.Set("private_key", v)
+	}
+
+	if v, ok := cred.Attributes["public_key"].(string); ok {
+		d.Set("public_key", v)
+	}
+
+	return diags
+}
+
+func resourceTrueNASSSHKeypairCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	keypair := sshKeyPair{
+		PrivateKey: d.Get("private_key").(string),
+		PublicKey:  d.Get("public_key").(string),
+	}
+
+	if keypair.PrivateKey == "" {
+		log.Printf("[DEBUG] Generating TrueNAS ssh keypair")
+
+		_, err := callAPI(ctx, c, http.MethodGet, "/keychaincredential/generate_ssh_key_pair", nil, &keypair)
+
+		if err != nil {
+			return diag.Errorf("error generating ssh keypair: %s", err)
+		}
+	}
+
+	input := expandSSHKeypair(d.Get("name").(string), keypair)
+
+	log.Printf("[DEBUG] Creating TrueNAS ssh keypair: %s", input.Name)
+
+	var cred keychainCredential
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/keychaincredential", input, &cred)
+
+	if err != nil {
+		return diag.Errorf("error creating ssh keypair: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(cred.ID))
+
+	log.Printf("[INFO] TrueNAS ssh keypair (%s) created", d.Id())
+
+	return resourceTrueNASSSHKeypairRead(ctx, d, m)
+}
+
+func resourceTrueNASSSHKeypairUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	// keys are ForceNew, only name can change
+	input := keychainCredential{
+		Name: d.Get("name").(string),
+		Attributes: map[string]interface{}{
+			"private_key": d.Get("private_key").(string),
+			"public_key":  d.Get("public_key").(string),
+		},
+	}
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/keychaincredential/id/%s", d.Id()), input, nil)
+
+	if err != nil {
+		return diag.Errorf("error updating ssh keypair: %s", err)
+	}
+
+	return resourceTrueNASSSHKeypairRead(ctx, d, m)
+}
+
+func resourceTrueNASSSHKeypairDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteKeychainCredential(ctx, d, m, "ssh keypair")
+}
+
+// deleteKeychainCredential deletes keychain entry, TrueNAS refuses to delete entries that are still in use
+func deleteKeychainCredential(ctx context.Context, d *schema.ResourceData, m interface{}, kind string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS %s: %s", kind, d.Id())
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/keychaincredential/id/%s", d.Id()), nil, nil)
+
+	if err != nil {
+		return diag.Errorf("error deleting %s: %s", kind, err)
+	}
+
+	log.Printf("[INFO] TrueNAS %s (%s) deleted", kind, d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+// expandSSHKeypair returns keychain entry for the keypair, TrueNAS derives public key if it is not set
+func expandSSHKeypair(name string, keypair sshKeyPair) keychainCredential {
+	attributes := map[string]interface{}{
+		"private_key": keypair.PrivateKey,
+	}
+
+	if keypair.PublicKey != "" {
+		attributes["public_key"] = keypair.PublicKey
+	}
+
+	return keychainCredential{
+		Name:       name,
+		Type:       "SSH_KEY_PAIR",
+		Attributes: attributes,
+	}
+}
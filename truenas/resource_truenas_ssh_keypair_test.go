@@ -0,0 +1,18 @@
+package truenas
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_expandSSHKeypair(t *testing.T) {
+	cred := expandSSHKeypair("backup", sshKeyPair{PrivateKey: "private"})
+
+	assert.Equal(t, "backup", cred.Name)
+	assert.Equal(t, "SSH_KEY_PAIR", cred.Type)
+	assert.Equal(t, map[string]interface{}{"private_key": "private"}, cred.Attributes)
+
+	cred = expandSSHKeypair("backup", sshKeyPair{PrivateKey: "private", PublicKey: "ssh-ed25519 AAAA"})
+
+	assert.Equal(t, "ssh-ed25519 AAAA", cred.Attributes["public_key"])
+}
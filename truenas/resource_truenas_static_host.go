@@ -0,0 +1,273 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// static hosts share a single network configuration field, updates are serialized
+// so that concurrently applied entries don't overwrite each other
+var staticHostsMutex sync.Mutex
+
+// networkHosts holds hosts field of network configuration, it is a newline separated
+// string on CORE and a list of lines on SCALE, updates are sent back in the same format
+type networkHosts struct {
+	lines  []string
+	isList bool
+}
+
+func resourceTrueNASStaticHost() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Static host entry (/etc/hosts) in TrueNAS network configuration, entries not managed by Terraform are left untouched",
+		CreateContext: resourceTrueNASStaticHostCreate,
+		ReadContext:   resourceTrueNASStaticHostRead,
+		UpdateContext: resourceTrueNASStaticHostUpdate,
+		DeleteContext: resourceTrueNASStaticHostDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"hostname": &schema.Schema{
+				Description: "Hostname",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"ip": &schema.Schema{
+				Description:  "IP address hostname resolves to",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsIPAddress,
+			},
+		},
+	}
+}
+
+func resourceTrueNASStaticHostRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	hosts, err := getNetworkHosts(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ip, ok := findStaticHost(hosts.lines, d.Id())
+
+	if !ok {
+		log.Printf("[WARN] Static host %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("hostname", d.Id())
+	d.Set("ip", ip)
+
+	return diags
+}
+
+func resourceTrueNASStaticHostCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	hostname := d.Get("hostname").(string)
+
+	staticHostsMutex.Lock()
+	defer staticHostsMutex.Unlock()
+
+	hosts, err := getNetworkHosts(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, ok := findStaticHost(hosts.lines, hostname); ok {
+		return diag.Errorf("static host %s already exists, import it instead", hostname)
+	}
+
+	hosts.lines = setStaticHost(hosts.lines, hostname, d.Get("ip").(string))
+
+	log.Printf("[DEBUG] Creating TrueNAS static host: %s", hostname)
+
+	if err := updateNetworkHosts(ctx, c, hosts); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hostname)
+
+	return resourceTrueNASStaticHostRead(ctx, d, m)
+}
+
+func resourceTrueNASStaticHostUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	staticHostsMutex.Lock()
+	defer staticHostsMutex.Unlock()
+
+	hosts, err := getNetworkHosts(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	hosts.lines = setStaticHost(hosts.lines, d.Id(), d.Get("ip").(string))
+
+	if err := updateNetworkHosts(ctx, c, hosts); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceTrueNASStaticHostRead(ctx, d, m)
+}
+
+func resourceTrueNASStaticHostDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+
+	staticHostsMutex.Lock()
+	defer staticHostsMutex.Unlock()
+
+	hosts, err := getNetworkHosts(ctx, c)
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Deleting TrueNAS static host: %s", d.Id())
+
+	hosts.lines = removeStaticHost(hosts.lines, d.Id())
+
+	if err := updateNetworkHosts(ctx, c, hosts); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] TrueNAS static host (%s) deleted", d.Id())
+	d.SetId("")
+
+	return diags
+}
+
+func getNetworkHosts(ctx context.Context, c *api.APIClient) (*networkHosts, error) {
+	var cfg struct {
+		Hosts json.RawMessage `json:"hosts"`
+	}
+
+	_, err := callAPI(ctx, c, http.MethodGet, "/network/configuration", nil, &cfg)
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting network configuration: %s", err)
+	}
+
+	return parseNetworkHosts(cfg.Hosts)
+}
+
+func parseNetworkHosts(raw json.RawMessage) (*networkHosts, error) {
+	hosts := &networkHosts{}
+
+	if err := json.Unmarshal(raw, &hosts.lines); err == nil {
+		hosts.isList = true
+		return hosts, nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("error decoding network configuration hosts: %s", err)
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			hosts.lines = append(hosts.lines, line)
+		}
+	}
+
+	return hosts, nil
+}
+
+func updateNetworkHosts(ctx context.Context, c *api.APIClient, hosts *networkHosts) error {
+	var value interface{} = strings.Join(hosts.lines, "\n")
+
+	if hosts.isList {
+		value = hosts.lines
+	}
+
+	_, err := callAPI(ctx, c, http.MethodPut, "/network/configuration", map[string]interface{}{"hosts": value}, nil)
+
+	if err != nil {
+		return fmt.Errorf("error updating network configuration hosts: %s", err)
+	}
+
+	return nil
+}
+
+// findStaticHost returns IP address of hostname, lines are in hosts(5) format: ip hostname [aliases...]
+func findStaticHost(lines []string, hostname string) (string, bool) {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if containsString(fields[1:], hostname) {
+			return fields[0], true
+		}
+	}
+
+	return "", false
+}
+
+// setStaticHost points hostname to ip, entry is updated in place if hostname has a line on its own
+func setStaticHost(lines []string, hostname string, ip string) []string {
+	entry := fmt.Sprintf("%s %s", ip, hostname)
+
+	for i, line := range lines {
+		fields := strings.Fields(line)
+
+		if len(fields) == 2 && fields[1] == hostname {
+			result := append([]string{}, lines...)
+			result[i] = entry
+			return result
+		}
+	}
+
+	return append(removeStaticHost(lines, hostname), entry)
+}
+
+// removeStaticHost removes hostname from its line, line is dropped if no other names are left
+func removeStaticHost(lines []string, hostname string) []string {
+	result := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || !containsString(fields[1:], hostname) {
+			result = append(result, line)
+			continue
+		}
+
+		names := make([]string, 0, len(fields)-1)
+
+		for _, name := range fields[1:] {
+			if name != hostname {
+				names = append(names, name)
+			}
+		}
+
+		if len(names) > 0 {
+			result = append(result, fmt.Sprintf("%s %s", fields[0], strings.Join(names, " ")))
+		}
+	}
+
+	return result
+}
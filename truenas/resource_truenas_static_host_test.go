@@ -0,0 +1,90 @@
+package truenas
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAccResourceTruenasStaticHost_basic(t *testing.T) {
+	suffix := acctest.RandStringFromCharSet(5, acctest.CharSetAlphaNum)
+	hostname := fmt.Sprintf("%s-%s.example.com", testResourcePrefix, suffix)
+	resourceName := "truenas_static_host.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasStaticHostConfig(hostname, "10.0.0.10"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "hostname", hostname),
+					resource.TestCheckResourceAttr(resourceName, "ip", "10.0.0.10"),
+				),
+			},
+			{
+				Config: testAccCheckResourceTruenasStaticHostConfig(hostname, "10.0.0.11"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "ip", "10.0.0.11"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_parseNetworkHosts(t *testing.T) {
+	hosts, err := parseNetworkHosts(json.RawMessage(`"10.0.0.1 backup\n\n10.0.0.2 nas2 nas2.example.com"`))
+
+	assert.NoError(t, err)
+	assert.False(t, hosts.isList)
+	assert.Equal(t, []string{"10.0.0.1 backup", "10.0.0.2 nas2 nas2.example.com"}, hosts.lines)
+
+	hosts, err = parseNetworkHosts(json.RawMessage(`["10.0.0.1 backup"]`))
+
+	assert.NoError(t, err)
+	assert.True(t, hosts.isList)
+	assert.Equal(t, []string{"10.0.0.1 backup"}, hosts.lines)
+}
+
+func Test_staticHosts(t *testing.T) {
+	lines := []string{"10.0.0.1 backup", "# 10.0.0.9 old", "10.0.0.2 nas2 nas2.example.com"}
+
+	ip, ok := findStaticHost(lines, "nas2.example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.2", ip)
+
+	_, ok = findStaticHost(lines, "old")
+	assert.False(t, ok)
+
+	assert.Equal(t,
+		[]string{"10.0.0.5 backup", "# 10.0.0.9 old", "10.0.0.2 nas2 nas2.example.com"},
+		setStaticHost(lines, "backup", "10.0.0.5"),
+	)
+
+	assert.Equal(t,
+		[]string{"10.0.0.1 backup", "# 10.0.0.9 old", "10.0.0.2 nas2", "10.0.0.3 nas2.example.com"},
+		setStaticHost(lines, "nas2.example.com", "10.0.0.3"),
+	)
+
+	assert.Equal(t,
+		[]string{"# 10.0.0.9 old", "10.0.0.2 nas2 nas2.example.com"},
+		removeStaticHost(lines, "backup"),
+	)
+}
+
+func testAccCheckResourceTruenasStaticHostConfig(hostname string, ip string) string {
+	return fmt.Sprintf(`
+	resource "truenas_static_host" "test" {
+		hostname = "%s"
+		ip = "%s"
+	}
+	`, hostname, ip)
+}
@@ -0,0 +1,249 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SYSCTL is available everywhere, LOADER and RC on CORE, ZFS and UDEV on SCALE
+var tunableTypes = []string{"SYSCTL", "LOADER", "RC", "ZFS", "UDEV"}
+
+// tunable types that are only applied on next boot
+var tunableRebootTypes = []string{"LOADER", "ZFS"}
+
+type tunable struct {
+	ID      int    `json:"id,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Var     string `json:"var,omitempty"`
+	Value   string `json:"value"`
+	Comment string `json:"comment"`
+	Enabled bool   `json:"enabled"`
+}
+
+func resourceTrueNASTunable() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Tunables set kernel, loader or rc.conf variables (CORE) or sysctl, ZFS module and udev settings (SCALE). `LOADER` and `ZFS` tunables are applied on next boot.",
+		CreateContext: resourceTrueNASTunableCreate,
+		ReadContext:   resourceTrueNASTunableRead,
+		UpdateContext: resourceTrueNASTunableUpdate,
+		DeleteContext: resourceTrueNASTunableDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"tunable_id": &schema.Schema{
+				Description: "Tunable ID",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"type": &schema.Schema{
+				Description:  "Tunable type: `SYSCTL`, `LOADER`, `RC` (CORE) or `SYSCTL`, `ZFS`, `UDEV` (SCALE)",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(tunableTypes, false),
+			},
+			"var": &schema.Schema{
+				Description: "Variable name, eg. `vfs.zfs.arc_max` or `zfs_arc_max`",
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+			},
+			"value": &schema.Schema{
+				Description: "Variable value",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"comment": &schema.Schema{
+				Description: "Optional tunable description",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"enabled": &schema.Schema{
+				Description: "`true` if tunable is enabled",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	}
+}
+
+func resourceTrueNASTunableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	c := m.(*api.APIClient)
+	id, err := strconv.Atoi(d.Id())
+
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var t tunable
+
+	resp, err := callAPI(ctx, c, http.MethodGet, fmt.Sprintf("/tunable/id/%d", id), nil, &t)
+
+	if err != nil {
+		// gracefully handle manual deletions
+		if resp != nil && resp.StatusCode == 404 {
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("error getting tunable: %s", err)
+	}
+
+	d.Set("tunable_id", t.ID)
+	d.Set("type", t.Type)
+	d.Set("var", t.Var)
+	d.Set("value", t.Value)
+	d.Set("comment", t.Comment)
+	d.Set("enabled", t.Enabled)
+
+	return diags
+}
+
+func resourceTrueNASTunableCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	input := tunable{
+		Type:    d.Get("type").(string),
+		Var:     d.Get("var").(string),
+		Value:   d.Get("value").(string),
+		Comment: d.Get("comment").(string),
+		Enabled: d.Get("enabled").(bool),
+	}
+
+	log.Printf("[DEBUG] Creating TrueNAS tunable: %+v", input)
+
+	var res json.RawMessage
+
+	_, err := callAPI(ctx, c, http.MethodPost, "/tunable", input, &res)
+
+	if err != nil {
+		return diag.Errorf("error creating tunable: %s", err)
+	}
+
+	t, err := tunableFromResponse(ctx, c, res, d.Timeout(schema.TimeoutCreate))
+
+	if err != nil {
+		return diag.Errorf("error creating tunable: %s", err)
+	}
+
+	d.SetId(strconv.Itoa(t.ID))
+
+	log.Printf("[INFO] TrueNAS tunable (%s) created", d.Id())
+
+	diags := resourceTrueNASTunableRead(ctx, d, m)
+
+	return append(diags, tunableRebootWarning(input.Type, input.Var)...)
+}
+
+func resourceTrueNASTunableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	// type and var can't be changed on SCALE, they are ForceNew
+	input := tunable{
+		Value:   d.Get("value").(string),
+		Comment: d.Get("comment").(string),
+		Enabled: d.Get("enabled").(bool),
+	}
+
+	var res json.RawMessage
+
+	_, err := callAPI(ctx, c, http.MethodPut, fmt.Sprintf("/tunable/id/%s", d.Id()), input, &res)
+
+	if err != nil {
+		return diag.Errorf("error updating tunable: %s", err)
+	}
+
+	if _, err := tunableFromResponse(ctx, c, res, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("error updating tunable: %s", err)
+	}
+
+	diags := resourceTrueNASTunableRead(ctx, d, m)
+
+	if d.HasChanges("value", "enabled") {
+		diags = append(diags, tunableRebootWarning(d.Get("type").(string), d.Get("var").(string))...)
+	}
+
+	return diags
+}
+
+func resourceTrueNASTunableDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*api.APIClient)
+
+	log.Printf("[DEBUG] Deleting TrueNAS tunable: %s", d.Id())
+
+	var res json.RawMessage
+
+	_, err := callAPI(ctx, c, http.MethodDelete, fmt.Sprintf("/tunable/id/%s", d.Id()), nil, &res)
+
+	if err != nil {
+		return diag.Errorf("error deleting tunable: %s", err)
+	}
+
+	// SCALE deletes tunables in a job
+	var jobID int
+
+	if err := json.Unmarshal(res, &jobID); err == nil && jobID > 0 {
+		if _, err := waitForJob(ctx, c, jobID, d.Timeout(schema.TimeoutDelete)); err != nil {
+			return diag.Errorf("error deleting tunable: %s", err)
+		}
+	}
+
+	log.Printf("[INFO] TrueNAS tunable (%s) deleted", d.Id())
+	d.SetId("")
+
+	return tunableRebootWarning(d.Get("type").(string), d.Get("var").(string))
+}
+
+// tunableFromResponse decodes create/update response, SCALE returns a job ID
+// instead of the tunable, in that case job result is used
+func tunableFromResponse(ctx context.Context, c *api.APIClient, res json.RawMessage, timeout time.Duration) (*tunable, error) {
+	var jobID int
+
+	if err := json.Unmarshal(res, &jobID); err == nil {
+		res, err = waitForJob(ctx, c, jobID, timeout)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var t tunable
+
+	if err := json.Unmarshal(res, &t); err != nil {
+		return nil, fmt.Errorf("error decoding tunable: %s", err)
+	}
+
+	return &t, nil
+}
+
+// tunableRebootWarning returns a warning if tunable of given type is only applied on next boot
+func tunableRebootWarning(tunableType string, name string) diag.Diagnostics {
+	if !containsString(tunableRebootTypes, tunableType) {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Reboot required to apply %s tunable %s", tunableType, name),
+			Detail:   fmt.Sprintf("%s tunables are only applied when TrueNAS boots, change to %s takes effect after next reboot.", tunableType, name),
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package truenas
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAccResourceTruenasTunable_basic(t *testing.T) {
+	resourceName := "truenas_tunable.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceTruenasTunableConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "type", "SYSCTL"),
+					resource.TestCheckResourceAttr(resourceName, "var", "net.inet.tcp.delayed_ack"),
+					resource.TestCheckResourceAttr(resourceName, "value", "0"),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "comment", "tf tunable"),
+					resource.TestCheckResourceAttrSet(resourceName, "tunable_id"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func Test_tunableRebootWarning(t *testing.T) {
+	assert.Nil(t, tunableRebootWarning("SYSCTL", "kern.ipc.somaxconn"))
+
+	diags := tunableRebootWarning("LOADER", "vfs.zfs.arc_max")
+
+	assert.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Len(t, tunableRebootWarning("ZFS", "zfs_arc_max"), 1)
+}
+
+func Test_tunableFromResponse(t *testing.T) {
+	res := json.RawMessage(`{"id": 3, "type": "SYSCTL", "var": "kern.ipc.somaxconn", "value": "2048", "comment": "", "enabled": true}`)
+
+	tun, err := tunableFromResponse(context.Background(), nil, res, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, tun.ID)
+	assert.Equal(t, "2048", tun.Value)
+}
+
+const testAccCheckResourceTruenasTunableConfig = `
+	resource "truenas_tunable" "test" {
+		type = "SYSCTL"
+		var = "net.inet.tcp.delayed_ack"
+		value = "0"
+		enabled = false
+		comment = "tf tunable"
+	}
+`
@@ -2,26 +2,29 @@ package truenas
 
 import (
 	"context"
+	"fmt"
 	api "github.com/dariusbakunas/truenas-go-sdk"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"log"
+	"net/http"
 	"strconv"
 	"strings"
 )
 
 func resourceTrueNASZVOL() *schema.Resource {
 	return &schema.Resource{
-		Description:   "Manage ZFS Volume (zvol), use of TF `prevent_destroy` (https://www.terraform.io/docs/language/meta-arguments/lifecycle.html#prevent_destroy) flag is recommended to avoid accidental deletion",
+		Description:   "Manage ZFS Volume (zvol), use of TF `prevent_destroy` (https://www.terraform.io/docs/language/meta-arguments/lifecycle.html#prevent_destroy) flag is recommended to avoid accidental deletion. `compression`, `deduplication`, `readonly` and `sync` accept `inherit` or `INHERIT` to inherit the value from parent dataset. Plan fails when a `volsize` change makes quotas, reservations and zvol sizes in the pool add up to more than the pool capacity. With `allow_pool_overcommit` set, a warning is reported after apply instead.",
 		CreateContext: resourceTrueNASZVOLCreate,
 		ReadContext:   resourceTrueNASZVOLRead,
 		UpdateContext: resourceTrueNASZVOLUpdate,
 		DeleteContext: resourceTrueNASZVOLDelete,
+		CustomizeDiff: resourceTrueNASZVOLCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
-		Schema: map[string]*schema.Schema{
+		Schema: withDatasetSpaceUsage(map[string]*schema.Schema{
 			"zvol_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
@@ -43,7 +46,7 @@ func resourceTrueNASZVOL() *schema.Resource {
 				Description:  "Compression level",
 				Type:         schema.TypeString,
 				Required:     true,
-				ValidateFunc: validation.StringInSlice(supportedCompression, false),
+				ValidateFunc: validation.StringInSlice(withInherit(supportedCompression), false),
 			},
 			"copies": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -54,7 +57,7 @@ func resourceTrueNASZVOL() *schema.Resource {
 				Type:         schema.TypeString,
 				Optional:     true,
 				Default:      "off",
-				ValidateFunc: validation.StringInSlice([]string{"on", "off", "verify"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"on", "off", "verify"}), false),
 			},
 			"encrypted": &schema.Schema{
 				Type:     schema.TypeBool,
@@ -90,11 +93,10 @@ func resourceTrueNASZVOL() *schema.Resource {
 				Computed: true,
 			},
 			"name": &schema.Schema{
-				Description:  "Unique identifier for the volume. Cannot be changed after the zvol is created.",
+				Description:  "Volume name, changing it (or `parent`) renames the volume in place, changing `pool` recreates it",
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validation.StringDoesNotContainAny("/"),
-				ForceNew:     true,
 			},
 			"inherit_encryption": &schema.Schema{
 				Type:        schema.TypeBool,
@@ -104,17 +106,17 @@ func resourceTrueNASZVOL() *schema.Resource {
 				Default:     false,
 			},
 			"parent": &schema.Schema{
-				Description: "Parent dataset",
+				Description: "Parent dataset, changing it moves the volume in place",
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "",
-				ForceNew:    true,
 			},
 			"pbkdf2iters": &schema.Schema{
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
 			"pool": &schema.Schema{
+				Description:  "Pool name, volumes cannot be moved between pools, changing it recreates the volume",
 				Type:         schema.TypeString,
 				Required:     true,
 				ValidateFunc: validation.StringDoesNotContainAny("/"),
@@ -125,7 +127,7 @@ func resourceTrueNASZVOL() *schema.Resource {
 				Description:  "Set to prevent the zvol from being modified",
 				Optional:     true,
 				Computed:     true,
-				ValidateFunc: validation.StringInSlice([]string{"on", "off", "inherit"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"on", "off"}), false),
 			},
 			"ref_reservation": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -140,14 +142,36 @@ func resourceTrueNASZVOL() *schema.Resource {
 				Description:  "Sets the data write synchronization. `inherit` takes the sync settings from the parent dataset, `standard` uses the settings that have been requested by the client software, `always` waits for data writes to complete, and `disabled` never waits for writes to complete.",
 				Optional:     true,
 				Default:      "standard",
-				ValidateFunc: validation.StringInSlice([]string{"always", "standard", "disabled", "inherit"}, false),
+				ValidateFunc: validation.StringInSlice(withInherit([]string{"always", "standard", "disabled"}), false),
 			},
 			"volsize": &schema.Schema{
-				Description: "Volume size in bytes, should be multiples of block size",
-				Type:        schema.TypeInt,
-				Required:    true,
+				Description:      "Volume size in bytes, rounded up to a multiple of `blocksize`",
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateFunc:     validation.IntAtLeast(1),
+				DiffSuppressFunc: suppressRoundedVolsize,
 			},
-		},
+			"allow_pool_overcommit": &schema.Schema{
+				Description: "Allow `volsize` changes that make quotas, reservations and zvol sizes in the pool add up to more than the pool capacity, these fail at plan time otherwise",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"allow_shrink": &schema.Schema{
+				Description: "Allow reducing `volsize`, data stored past the new size is lost. Volume can never be shrunk below the space used by its data, or while it is attached to a running VM or an iSCSI extent. Growing is allowed while the volume is in use.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"all_user_properties": &schema.Schema{
+				Description: "Read back all locally set user properties, so that user properties not in `user_properties` are removed",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"user_properties":  resourceUserPropertiesSchema(),
+			"property_sources": resourcePropertySourcesSchema(),
+		}),
 	}
 }
 
@@ -195,11 +219,11 @@ func resourceTrueNASZVOLRead(ctx context.Context, d *schema.ResourceData, m inte
 	}
 
 	if resp.Compression != nil {
-		d.Set("compression", strings.ToLower(*resp.Compression.Value))
+		d.Set("compression", flattenInheritableProperty(d, "compression", resp.Compression))
 	}
 
 	if resp.Deduplication != nil {
-		d.Set("deduplication", strings.ToLower(*resp.Deduplication.Value))
+		d.Set("deduplication", flattenInheritableProperty(d, "deduplication", resp.Deduplication))
 	}
 
 	if resp.KeyFormat != nil && resp.KeyFormat.Value != nil {
@@ -237,7 +261,7 @@ func resourceTrueNASZVOLRead(ctx context.Context, d *schema.ResourceData, m inte
 	}
 
 	if resp.Readonly != nil {
-		d.Set("readonly", strings.ToLower(*resp.Readonly.Value))
+		d.Set("readonly", flattenInheritableProperty(d, "readonly", resp.Readonly))
 	}
 
 	if resp.EncryptionAlgorithm != nil && resp.EncryptionAlgorithm.Value != nil {
@@ -265,13 +289,32 @@ func resourceTrueNASZVOLRead(ctx context.Context, d *schema.ResourceData, m inte
 	}
 
 	if resp.Sync != nil {
-		d.Set("sync", strings.ToLower(*resp.Sync.Value))
+		d.Set("sync", flattenInheritableProperty(d, "sync", resp.Sync))
 	}
 
 	if resp.Encrypted != nil {
 		d.Set("encrypted", *resp.Encrypted)
 	}
 
+	if err := setUserProperties(resp, d); err != nil {
+		return diag.Errorf("error setting user_properties: %s", err)
+	}
+
+	sources := flattenPropertySources(map[string]*api.CompositeValue{
+		"compression":   resp.Compression,
+		"deduplication": resp.Deduplication,
+		"readonly":      resp.Readonly,
+		"sync":          resp.Sync,
+	})
+
+	if err := d.Set("property_sources", sources); err != nil {
+		return diag.Errorf("error setting property_sources: %s", err)
+	}
+
+	if err := setDatasetSpaceUsage(resp, d); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.Set("zvol_id", id)
 
 	return diags
@@ -294,7 +337,13 @@ func resourceTrueNASZVOLCreate(ctx context.Context, d *schema.ResourceData, m in
 
 	d.SetId(resp.Id)
 
-	return resourceTrueNASZVOLRead(ctx, d, m)
+	diags := resourceTrueNASZVOLRead(ctx, d, m)
+
+	if !diags.HasError() {
+		diags = append(diags, poolCapacityWarning(ctx, c, d.Get("pool").(string))...)
+	}
+
+	return diags
 }
 
 func resourceTrueNASZVOLDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -324,22 +373,23 @@ func resourceTrueNASZVOLDelete(ctx context.Context, d *schema.ResourceData, m in
 func resourceTrueNASZVOLUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(*api.APIClient)
 
+	if d.HasChanges("parent", "name") {
+		if err := renameDataset(ctx, c, d); err != nil {
+			return diag.Errorf("error renaming zvol: %s", err)
+		}
+	}
+
 	input := api.UpdateDatasetParams{}
 
 	if d.HasChange("comments") {
 		input.Comments = getStringPtr(d.Get("comments").(string))
 	}
 
-	if d.HasChange("volsize") {
-		o, n := d.GetChange("volsize")
-		oldSz := o.(int)
-		newSz := n.(int)
-
-		if newSz < oldSz {
-			return diag.Errorf("zvol volume size can only be increased, recreate the volume to shrink")
-		}
+	volsize := roundVolsize(d.Get("volsize").(int), d.Get("blocksize").(string))
 
-		input.Volsize = getInt64Ptr(int64(newSz))
+	// shrinking is validated in CustomizeDiff
+	if d.HasChange("volsize") {
+		input.Volsize = getInt64Ptr(int64(volsize))
 	}
 
 	if d.HasChange("sync") {
@@ -358,10 +408,16 @@ func resourceTrueNASZVOLUpdate(ctx context.Context, d *schema.ResourceData, m in
 		input.Readonly = getStringPtr(strings.ToUpper(d.Get("readonly").(string)))
 	}
 
-	if d.HasChange("force_size") {
+	if d.HasChanges("force_size", "volsize") {
 		input.ForceSize = getBoolPtr(d.Get("force_size").(bool))
 	}
 
+	if d.HasChange("user_properties") {
+		o, n := d.GetChange("user_properties")
+
+		setAdditionalProperty(&input.AdditionalProperties, "user_properties_update", expandUserPropertiesUpdate(o.(map[string]interface{}), n.(map[string]interface{})))
+	}
+
 	_, _, err := c.DatasetApi.UpdateDataset(ctx, d.Id()).UpdateDatasetParams(input).Execute()
 
 	if err != nil {
@@ -372,7 +428,19 @@ func resourceTrueNASZVOLUpdate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.Errorf("error updating zvol: %s\n%s", err, body)
 	}
 
-	return resourceTrueNASZVOLRead(ctx, d, m)
+	if d.HasChange("volsize") {
+		if err := verifyZvolSize(ctx, c, d.Id(), volsize); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	diags := resourceTrueNASZVOLRead(ctx, d, m)
+
+	if !diags.HasError() && d.HasChanges("volsize", "reservation", "ref_reservation") {
+		diags = append(diags, poolCapacityWarning(ctx, c, d.Get("pool").(string))...)
+	}
+
+	return diags
 }
 
 func expandZvol(d *schema.ResourceData) api.CreateDatasetParams {
@@ -416,12 +484,221 @@ func expandZvol(d *schema.ResourceData) api.CreateDatasetParams {
 	}
 
 	if volSize, ok := d.GetOk("volsize"); ok {
-		input.Volsize = getInt64Ptr(int64(volSize.(int)))
+		input.Volsize = getInt64Ptr(int64(roundVolsize(volSize.(int), d.Get("blocksize").(string))))
 	}
 
 	if blockSize, ok := d.GetOk("blocksize"); ok {
 		input.Volblocksize = getStringPtr(blockSize.(string))
 	}
 
+	setAdditionalProperty(&input.AdditionalProperties, "user_properties", expandManagedUserProperties(d.Get("user_properties").(map[string]interface{})))
+
 	return input
 }
+
+// parseBlockSize converts volblocksize, eg. 16K, to bytes
+func parseBlockSize(size string) int {
+	if strings.HasSuffix(size, "K") {
+		n, err := strconv.Atoi(strings.TrimSuffix(size, "K"))
+
+		if err != nil {
+			return 0
+		}
+
+		return n * 1024
+	}
+
+	n, err := strconv.Atoi(size)
+
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// roundVolsize rounds size up to a multiple of blocksize, ZFS refuses other sizes
+func roundVolsize(size int, blocksize string) int {
+	bs := parseBlockSize(blocksize)
+
+	if bs <= 0 || size%bs == 0 {
+		return size
+	}
+
+	return (size/bs + 1) * bs
+}
+
+func suppressRoundedVolsize(k, old, new string, d *schema.ResourceData) bool {
+	o, err := strconv.Atoi(old)
+
+	if err != nil {
+		return false
+	}
+
+	n, err := strconv.Atoi(new)
+
+	if err != nil {
+		return false
+	}
+
+	return o == roundVolsize(n, d.Get("blocksize").(string))
+}
+
+// validateZvolResize refuses shrinking unless allowed and never lets volume get smaller than its data
+func validateZvolResize(oldSize int, newSize int, used int, allowShrink bool) error {
+	if newSize >= oldSize {
+		return nil
+	}
+
+	if !allowShrink {
+		return fmt.Errorf("zvol volsize can only be increased (%d -> %d), set allow_shrink to shrink the volume", oldSize, newSize)
+	}
+
+	if newSize < used {
+		return fmt.Errorf("zvol volsize %d is smaller than %d bytes used by its data", newSize, used)
+	}
+
+	return nil
+}
+
+// resourceTrueNASZVOLCustomizeDiff plans new zvol_id on rename and checks volsize changes
+// against pool capacity, used space and volume consumers
+func resourceTrueNASZVOLCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// rename changes the ID, dependents must not plan with the old device path
+	if d.Id() != "" && d.HasChanges("parent", "name") {
+		if err := d.SetNewComputed("zvol_id"); err != nil {
+			return err
+		}
+	}
+
+	if !d.HasChange("volsize") {
+		return nil
+	}
+
+	if !d.Get("allow_pool_overcommit").(bool) {
+		// reservations are computed, current ones still count until the volume is resized
+		if planned, grows := plannedCommitment(d, "volsize", "reservation", "ref_reservation"); grows {
+			if err := validatePoolCapacity(ctx, m.(*api.APIClient), d, planned); err != nil {
+				return err
+			}
+		}
+	}
+
+	// replacement creates a new volume, there is nothing to shrink
+	if d.Id() == "" || d.HasChanges("pool", "blocksize", "inherit_encryption", "encryption_algorithm") {
+		return nil
+	}
+
+	o, n := d.GetChange("volsize")
+	blocksize := d.Get("blocksize").(string)
+	oldSize := o.(int)
+	newSize := roundVolsize(n.(int), blocksize)
+
+	if newSize >= oldSize {
+		return nil
+	}
+
+	c := m.(*api.APIClient)
+
+	resp, _, err := c.DatasetApi.GetDataset(ctx, d.Id()).Execute()
+
+	if err != nil {
+		return fmt.Errorf("error getting zvol: %s", err)
+	}
+
+	used, ok := getCompositeValueBytes(resp.AdditionalProperties, "usedbydataset")
+
+	if !ok {
+		used, _ = getCompositeValueBytes(resp.AdditionalProperties, "referenced")
+	}
+
+	if err := validateZvolResize(oldSize, newSize, used, d.Get("allow_shrink").(bool)); err != nil {
+		return err
+	}
+
+	consumers, err := getZvolConsumers(ctx, c, d.Id())
+
+	if err != nil {
+		return err
+	}
+
+	if len(consumers) > 0 {
+		return fmt.Errorf("zvol %s can not be shrunk while it is used by %s, stop the VM or remove the extent first", d.Id(), strings.Join(consumers, ", "))
+	}
+
+	return nil
+}
+
+type iscsiExtent struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Disk string `json:"disk"`
+}
+
+// getZvolConsumers returns running VMs and iSCSI extents using the zvol, shrinking the volume
+// under them corrupts guest or initiator file systems
+func getZvolConsumers(ctx context.Context, c *api.APIClient, id string) ([]string, error) {
+	vms, _, err := c.VmApi.ListVMS(ctx).Execute()
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing VMs: %s", err)
+	}
+
+	var extents []iscsiExtent
+
+	if _, err := callAPI(ctx, c, http.MethodGet, "/iscsi/extent", nil, &extents); err != nil {
+		return nil, fmt.Errorf("error listing iSCSI extents: %s", err)
+	}
+
+	return findZvolConsumers(id, vms, extents), nil
+}
+
+func findZvolConsumers(id string, vms []api.VM, extents []iscsiExtent) []string {
+	result := []string{}
+
+	for _, vm := range vms {
+		if vm.Status == nil || vm.Status.State == nil || *vm.Status.State != "RUNNING" {
+			continue
+		}
+
+		for _, dev := range vm.Devices {
+			if path, ok := dev.Attributes["path"].(string); ok && dev.Dtype == "DISK" && path == "/dev/zvol/"+id {
+				result = append(result, fmt.Sprintf("running VM %s", vm.Name))
+				break
+			}
+		}
+	}
+
+	for _, ext := range extents {
+		if ext.Type == "DISK" && ext.Disk == "zvol/"+id {
+			result = append(result, fmt.Sprintf("iSCSI extent %s", ext.Name))
+		}
+	}
+
+	return result
+}
+
+// verifyZvolSize makes sure zvol was resized, TrueNAS may silently keep the old size
+func verifyZvolSize(ctx context.Context, c *api.APIClient, id string, expected int) error {
+	resp, _, err := c.DatasetApi.GetDataset(ctx, id).Execute()
+
+	if err != nil {
+		return fmt.Errorf("error verifying zvol size: %s", err)
+	}
+
+	if resp.Volsize == nil {
+		return fmt.Errorf("error verifying zvol size: volsize not returned")
+	}
+
+	size, err := strconv.Atoi(resp.Volsize.Rawvalue)
+
+	if err != nil {
+		return fmt.Errorf("error parsing volsize rawvalue: %s", err)
+	}
+
+	if size != expected {
+		return fmt.Errorf("zvol %s was not resized, volsize is %d, expected %d", id, size, expected)
+	}
+
+	return nil
+}
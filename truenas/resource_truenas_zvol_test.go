@@ -0,0 +1,204 @@
+package truenas
+
+import (
+	"context"
+	"fmt"
+	api "github.com/dariusbakunas/truenas-go-sdk"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_expandZvol(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTrueNASZVOL().Schema, map[string]interface{}{
+		"pool":      "Tank",
+		"parent":    "vm",
+		"name":      "disk0",
+		"volsize":   1073741824,
+		"blocksize": "16K",
+		"user_properties": map[string]interface{}{
+			"com.example:owner": "ops",
+		},
+	})
+
+	input := expandZvol(d)
+
+	assert.Equal(t, "Tank/vm/disk0", input.Name)
+	assert.Equal(t, "VOLUME", *input.Type)
+	assert.Equal(t, int64(1073741824), *input.Volsize)
+	assert.Equal(t, []map[string]interface{}{{"key": "com.example:owner", "value": "ops"}, {"key": managedUserProperty, "value": "true"}}, input.AdditionalProperties["user_properties"])
+}
+
+func Test_roundVolsize(t *testing.T) {
+	testcases := []struct {
+		size      int
+		blocksize string
+		expected  int
+	}{
+		{size: 1073741824, blocksize: "32K", expected: 1073741824},
+		{size: 1000000000, blocksize: "32K", expected: 1000013824},
+		{size: 1000, blocksize: "512", expected: 1024},
+		{size: 1000, blocksize: "", expected: 1000},
+	}
+
+	for _, c := range testcases {
+		assert.Equal(t, c.expected, roundVolsize(c.size, c.blocksize))
+	}
+}
+
+func Test_validateZvolResize(t *testing.T) {
+	gib := 1024 * 1024 * 1024
+
+	assert.NoError(t, validateZvolResize(gib, 2*gib, gib, false))
+	assert.Error(t, validateZvolResize(2*gib, gib, 0, false))
+	assert.NoError(t, validateZvolResize(2*gib, gib, gib/2, true))
+	assert.Error(t, validateZvolResize(4*gib, gib, 2*gib, true))
+}
+
+func Test_getCompositeValueBytes(t *testing.T) {
+	props := map[string]interface{}{
+		"usedbydataset": map[string]interface{}{"value": "1G", "rawvalue": "1073741824"},
+		"broken":        "1G",
+	}
+
+	used, ok := getCompositeValueBytes(props, "usedbydataset")
+
+	assert.True(t, ok)
+	assert.Equal(t, 1073741824, used)
+
+	_, ok = getCompositeValueBytes(props, "broken")
+
+	assert.False(t, ok)
+
+	_, ok = getCompositeValueBytes(props, "missing")
+
+	assert.False(t, ok)
+}
+
+func Test_resourceTrueNASZVOLCustomizeDiff_rename(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "Tank/vm/disk0",
+		Attributes: map[string]string{
+			"id":        "Tank/vm/disk0",
+			"zvol_id":   "Tank/vm/disk0",
+			"pool":      "Tank",
+			"parent":    "vm",
+			"name":      "disk0",
+			"volsize":   "1073741824",
+			"blocksize": "16K",
+			// ForceNew, missing value would plan replacement
+			"inherit_encryption": "false",
+		},
+	}
+
+	diff, err := resourceTrueNASZVOL().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":      "Tank",
+		"parent":    "vms",
+		"name":      "disk0",
+		"volsize":   1073741824,
+		"blocksize": "16K",
+	}), nil)
+
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	assert.True(t, diff.Attributes["zvol_id"].NewComputed)
+}
+
+func Test_resourceTrueNASZVOLCustomizeDiff_replace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	state := &terraform.InstanceState{
+		ID: "Tank/vm/disk0",
+		Attributes: map[string]string{
+			"id":                 "Tank/vm/disk0",
+			"pool":               "Tank",
+			"parent":             "vm",
+			"name":               "disk0",
+			"volsize":            "2147483648",
+			"blocksize":          "16K",
+			"inherit_encryption": "false",
+		},
+	}
+
+	// new volume replaces the old one, shrink guard does not apply
+	diff, err := resourceTrueNASZVOL().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"pool":      "Tank",
+		"parent":    "vm",
+		"name":      "disk0",
+		"volsize":   1073741824,
+		"blocksize": "64K",
+	}), newTestAPIClient(server.URL))
+
+	assert.NoError(t, err)
+	assert.True(t, diff.RequiresNew())
+}
+
+func Test_resourceTrueNASZVOLCustomizeDiff_poolCapacity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "Tank", "name": "Tank", "pool": "Tank", "type": "FILESYSTEM",
+			"used": {"rawvalue": "2147483648"}, "available": {"rawvalue": "2147483648"},
+			"children": [
+				{"id": "Tank/vm/disk0", "name": "Tank/vm/disk0", "pool": "Tank", "type": "VOLUME", "volsize": {"rawvalue": "1073741824"}, "children": []},
+				{"id": "Tank/vm/disk1", "name": "Tank/vm/disk1", "pool": "Tank", "type": "VOLUME", "volsize": {"rawvalue": "2147483648"}, "children": []}
+			]}]`)
+	}))
+	defer server.Close()
+
+	state := &terraform.InstanceState{
+		ID: "Tank/vm/disk0",
+		Attributes: map[string]string{
+			"id":                 "Tank/vm/disk0",
+			"pool":               "Tank",
+			"parent":             "vm",
+			"name":               "disk0",
+			"volsize":            "1073741824",
+			"blocksize":          "16K",
+			"inherit_encryption": "false",
+		},
+	}
+
+	config := map[string]interface{}{
+		"pool":      "Tank",
+		"parent":    "vm",
+		"name":      "disk0",
+		"volsize":   3221225472,
+		"blocksize": "16K",
+	}
+
+	_, err := resourceTrueNASZVOL().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), newTestAPIClient(server.URL))
+
+	assert.ErrorContains(t, err, "set allow_pool_overcommit")
+
+	config["allow_pool_overcommit"] = true
+
+	_, err = resourceTrueNASZVOL().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), newTestAPIClient(server.URL))
+
+	assert.NoError(t, err)
+}
+
+func Test_findZvolConsumers(t *testing.T) {
+	running := "RUNNING"
+	stopped := "STOPPED"
+	disk := func(path string) api.VMDevice {
+		return api.VMDevice{Dtype: "DISK", Attributes: map[string]interface{}{"path": path}}
+	}
+
+	vms := []api.VM{
+		{Name: "web", Status: &api.VMStatus{State: &running}, Devices: []api.VMDevice{disk("/dev/zvol/Tank/vm/web")}},
+		{Name: "db", Status: &api.VMStatus{State: &stopped}, Devices: []api.VMDevice{disk("/dev/zvol/Tank/vm/db")}},
+	}
+	extents := []iscsiExtent{
+		{Name: "lun0", Type: "DISK", Disk: "zvol/Tank/iscsi/lun0"},
+		{Name: "file", Type: "FILE", Disk: ""},
+	}
+
+	assert.Equal(t, []string{"running VM web"}, findZvolConsumers("Tank/vm/web", vms, extents))
+	assert.Empty(t, findZvolConsumers("Tank/vm/db", vms, extents))
+	assert.Equal(t, []string{"iSCSI extent lun0"}, findZvolConsumers("Tank/iscsi/lun0", vms, extents))
+}